@@ -2,6 +2,10 @@ package hoff
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 )
 
 // ActionNode is a type of Node who compute a function
@@ -9,12 +13,22 @@ import (
 type ActionNode struct {
 	name       string
 	actionFunc func(*Context) error
+
+	pure     bool
+	inputs   []string
+	cacheKey func(inputs map[string]interface{}) string
+	cache    Cache
 }
 
 func (n ActionNode) String() string {
 	return n.name
 }
 
+// ID give the name of the action node as identifier.
+func (n *ActionNode) ID() string {
+	return n.name
+}
+
 // Compute run the action function and decide which compute state to return.
 func (n *ActionNode) Compute(c *Context) ComputeState {
 	err := n.actionFunc(c)
@@ -29,8 +43,164 @@ func (n *ActionNode) DecideCapability() bool {
 	return false
 }
 
+// ConfigurePure mark the action node as pure, its context writes only depend on the values of some context keys (its inputs).
+// A computation skip a pure action node and apply the cached context writes when the cache key of its inputs is already known.
+// Without cache key function, the cache key is made of the inputs values with their types
+// (the pointed values being used instead of the pointers).
+func (n *ActionNode) ConfigurePure(inputs []string, cacheKey func(inputs map[string]interface{}) string) {
+	n.pure = true
+	n.inputs = append(make([]string, 0, len(inputs)), inputs...)
+	n.cacheKey = cacheKey
+	if n.cache == nil {
+		n.cache = NewLRUCache(DefaultCacheCapacity, DefaultCacheTTL)
+	}
+}
+
+// ConfigureCache replace the in-memory cache of a pure action node.
+func (n *ActionNode) ConfigureCache(cache Cache) error {
+	if cache == nil {
+		return errors.New("cache need to be defined")
+	}
+	n.cache = cache
+	return nil
+}
+
+// CacheKey give the cache key of the inputs of a pure action node (prefixed by the node name).
+// The inputs with a cyclic value are not cacheable without a cache key function.
+func (n *ActionNode) CacheKey(c *Context) (string, bool) {
+	if !n.pure {
+		return "", false
+	}
+	inputs := make(map[string]interface{}, len(n.inputs))
+	for _, input := range n.inputs {
+		if value, found := c.Read(input); found {
+			inputs[input] = value
+		}
+	}
+	if n.cacheKey != nil {
+		return fmt.Sprintf("%v:%v", n.name, n.cacheKey(inputs)), true
+	}
+
+	var b strings.Builder
+	b.WriteString(n.name)
+	b.WriteString(":")
+	visiting := make(map[deepCopyKey]bool)
+	for i, input := range sortedKeys(inputs) {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, "%q=", input)
+		if err := writeCacheKeyValue(&b, reflect.ValueOf(inputs[input]), visiting); err != nil {
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeCacheKeyValue write a value with its type, and the types of its nested values,
+// the map entries being sorted, and the pointers being replaced by the pointed values.
+// The maps, slices, and pointers being written are visiting, to fail on a cyclic value.
+func writeCacheKeyValue(b *strings.Builder, value reflect.Value, visiting map[deepCopyKey]bool) error {
+	if !value.IsValid() {
+		b.WriteString("nil")
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			break
+		}
+		key := deepCopyKey{pointer: value.Pointer(), valueType: value.Type()}
+		if value.Kind() == reflect.Slice {
+			key.length = value.Len()
+		}
+		if visiting[key] {
+			return errors.New("can't compute cache key of cyclic value")
+		}
+		visiting[key] = true
+		defer delete(visiting, key)
+	}
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			fmt.Fprintf(b, "%v(nil)", value.Type())
+			return nil
+		}
+		return writeCacheKeyValue(b, value.Elem(), visiting)
+	case reflect.Ptr:
+		if value.IsNil() {
+			fmt.Fprintf(b, "%v(nil)", value.Type())
+			return nil
+		}
+		b.WriteString("&")
+		return writeCacheKeyValue(b, value.Elem(), visiting)
+	case reflect.Map:
+		entries := make([]string, 0, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			var entry strings.Builder
+			if err := writeCacheKeyValue(&entry, iter.Key(), visiting); err != nil {
+				return err
+			}
+			entry.WriteString(":")
+			if err := writeCacheKeyValue(&entry, iter.Value(), visiting); err != nil {
+				return err
+			}
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		fmt.Fprintf(b, "%v{%v}", value.Type(), strings.Join(entries, ","))
+	case reflect.Slice, reflect.Array:
+		fmt.Fprintf(b, "%v{", value.Type())
+		for i := 0; i < value.Len(); i++ {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			if err := writeCacheKeyValue(b, value.Index(i), visiting); err != nil {
+				return err
+			}
+		}
+		b.WriteString("}")
+	case reflect.Struct:
+		fmt.Fprintf(b, "%v{", value.Type())
+		for i := 0; i < value.NumField(); i++ {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(b, "%v:", value.Type().Field(i).Name)
+			if err := writeCacheKeyValue(b, value.Field(i), visiting); err != nil {
+				return err
+			}
+		}
+		b.WriteString("}")
+	case reflect.String:
+		fmt.Fprintf(b, "%v(%q)", value.Type(), value.String())
+	default:
+		fmt.Fprintf(b, "%v(%v)", value.Type(), value)
+	}
+	return nil
+}
+
+// Cache give the cache of a pure action node.
+func (n *ActionNode) Cache() Cache {
+	return n.cache
+}
+
 // NewActionNode create a ActionNode based on a name and a function to realize the needed action.
 func NewActionNode(name string, actionFunc func(*Context) error) (*ActionNode, error) {
+	if name == "" {
+		return nil, errors.New("can't create action node without name")
+	}
 	if actionFunc == nil {
 		return nil, errors.New("can't create action node without function")
 	}
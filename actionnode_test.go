@@ -2,6 +2,9 @@ package hoff
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -69,3 +72,93 @@ func Test_ActionNode_String(t *testing.T) {
 		t.Error("action node must print its name")
 	}
 }
+
+func Test_NewActionNode_without_name(t *testing.T) {
+	node, err := NewActionNode("", func(*Context) error { return nil })
+
+	expectedError := errors.New("can't create action node without name")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+	if node != nil {
+		t.Errorf("action node - got: %+v, want: <nil>", node)
+	}
+}
+
+func Test_ActionNode_CacheKey(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	context := NewContext(map[string]interface{}{"a": 1, "b": 2, "c": 3})
+	if _, cacheable := node.CacheKey(context); cacheable {
+		t.Error("action node must not be cacheable without being pure")
+	}
+
+	node.ConfigurePure([]string{"b", "a", "missing"}, nil)
+	key, cacheable := node.CacheKey(context)
+	if !cacheable || key != `node:"a"=int(1),"b"=int(2)` {
+		t.Errorf("default cache key - got: %+v (%+v), want: %+v", key, cacheable, `node:"a"=int(1),"b"=int(2)`)
+	}
+
+	node.ConfigurePure([]string{"a"}, func(inputs map[string]interface{}) string {
+		return fmt.Sprintf("a=%v", inputs["a"])
+	})
+	key, _ = node.CacheKey(context)
+	if key != "node:a=1" {
+		t.Errorf("cache key - got: %+v, want: %+v", key, "node:a=1")
+	}
+
+	err := node.ConfigureCache(nil)
+	expectedError := errors.New("cache need to be defined")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_ActionNode_CacheKey_with_typed_inputs(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	node.ConfigurePure([]string{"a"}, nil)
+	keyOf := func(value interface{}) string {
+		key, _ := node.CacheKey(NewContext(map[string]interface{}{"a": value}))
+		return key
+	}
+
+	keys := map[string]interface{}{}
+	for _, value := range []interface{}{1, "1", 1.0, int64(1), []interface{}{1}, []interface{}{"1"}, nil} {
+		key := keyOf(value)
+		if other, found := keys[key]; found {
+			t.Errorf("cache key - got: %+v for %#v and %#v", key, value, other)
+		}
+		keys[key] = value
+	}
+
+	first, second := 1, 1
+	if keyOf(&first) != keyOf(&second) {
+		t.Errorf("cache key of pointers - got: %+v and %+v", keyOf(&first), keyOf(&second))
+	}
+	if keyOf(map[string]interface{}{"x": 1, "y": "2"}) != keyOf(map[string]interface{}{"y": "2", "x": 1}) {
+		t.Errorf("cache key of maps - got: %+v", keyOf(map[string]interface{}{"x": 1, "y": "2"}))
+	}
+}
+
+func Test_ActionNode_CacheKey_with_cyclic_inputs(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	node.ConfigurePure([]string{"a"}, nil)
+
+	loop := map[string]interface{}{}
+	loop["self"] = loop
+	if key, cacheable := node.CacheKey(NewContext(map[string]interface{}{"a": loop})); cacheable {
+		t.Errorf("cyclic input must not be cacheable - got: %+v", key)
+	}
+
+	var b strings.Builder
+	err := writeCacheKeyValue(&b, reflect.ValueOf(loop), make(map[deepCopyKey]bool))
+	expectedError := errors.New("can't compute cache key of cyclic value")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	shared := []interface{}{1}
+	key, cacheable := node.CacheKey(NewContext(map[string]interface{}{"a": []interface{}{shared, shared}}))
+	if !cacheable || key != `node:"a"=[]interface {}{[]interface {}{int(1)},[]interface {}{int(1)}}` {
+		t.Errorf("shared input - got: %+v (%+v)", key, cacheable)
+	}
+}
@@ -0,0 +1,67 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConfigureBulkhead limit the number of concurrent computations of a node into the system before activation.
+// The bulkhead is shared by all computations of the activated node system, and of its clones, like the ones of an Engine,
+// a computation waiting for a free place in the bulkhead before computing the node.
+func (s *NodeSystem) ConfigureBulkhead(n Node, max int) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't configure bulkhead, node system is freeze due to activation")
+	}
+	if !s.haveNode(n) {
+		return false, fmt.Errorf("can't configure bulkhead of undeclared node: %+v", n)
+	}
+	if max < 1 {
+		return false, fmt.Errorf("can't configure bulkhead without positive maximum: %v", max)
+	}
+	s.nodesBulkheads[n] = newBulkhead(max)
+	return true, nil
+}
+
+// BulkheadOfNode get the configured maximum of concurrent computations of a node
+func (s *NodeSystem) BulkheadOfNode(n Node) (int, bool) {
+	bulkhead, found := s.nodesBulkheads[n]
+	if !found {
+		return 0, false
+	}
+	return cap(bulkhead.places), true
+}
+
+// bulkhead hold the places of the concurrent computations of a node.
+type bulkhead struct {
+	places chan struct{}
+}
+
+func newBulkhead(max int) *bulkhead {
+	return &bulkhead{
+		places: make(chan struct{}, max),
+	}
+}
+
+// enter take a place in the bulkhead, waiting for a free place until the context is done,
+// and give the waited duration.
+func (b *bulkhead) enter(ctx context.Context) (time.Duration, error) {
+	select {
+	case b.places <- struct{}{}:
+		return 0, nil
+	default:
+	}
+	start := time.Now()
+	select {
+	case b.places <- struct{}{}:
+		return time.Since(start), nil
+	case <-ctx.Done():
+		return time.Since(start), ctx.Err()
+	}
+}
+
+// leave free a place in the bulkhead.
+func (b *bulkhead) leave() {
+	<-b.places
+}
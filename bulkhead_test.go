@@ -0,0 +1,192 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ConfigureBulkhead(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	activatedSystem := NewNodeSystem()
+	activatedSystem.AddNode(node)
+	activatedSystem.Activate()
+
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenNode     Node
+		givenMax      int
+		expectedMax   int
+		expectedError error
+	}{
+		{
+			name:        "Can configure a bulkhead",
+			givenNode:   node,
+			givenMax:    4,
+			expectedMax: 4,
+		},
+		{
+			name:          "Can't configure a bulkhead on an activated node system",
+			givenSystem:   activatedSystem,
+			givenNode:     node,
+			givenMax:      4,
+			expectedError: errors.New("can't configure bulkhead, node system is freeze due to activation"),
+		},
+		{
+			name:          "Can't configure a bulkhead of an undeclared node",
+			givenNode:     &SomeNode{},
+			givenMax:      4,
+			expectedError: errors.New("can't configure bulkhead of undeclared node: &{}"),
+		},
+		{
+			name:          "Can't configure a bulkhead without place",
+			givenNode:     node,
+			expectedError: errors.New("can't configure bulkhead without positive maximum: 0"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			system := testCase.givenSystem
+			if system == nil {
+				system = NewNodeSystem()
+				system.AddNode(node)
+			}
+			ok, err := system.ConfigureBulkhead(testCase.givenNode, testCase.givenMax)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if ok != (testCase.expectedError == nil) {
+				t.Errorf("ok - got: %+v, want: %+v", ok, testCase.expectedError == nil)
+			}
+			max, _ := system.BulkheadOfNode(testCase.givenNode)
+			if max != testCase.expectedMax {
+				t.Errorf("max - got: %+v, want: %+v", max, testCase.expectedMax)
+			}
+		})
+	}
+}
+
+func Test_Bulkhead_Engine(t *testing.T) {
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	writeDatabase, _ := NewActionNode("writeDatabase", func(c *Context) error {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return nil
+	})
+	system := NewNodeSystem()
+	system.AddNode(writeDatabase)
+	system.ConfigureBulkhead(writeDatabase, 2)
+	system.Activate()
+	engine := NewEngine(ParallelComputation)
+	engine.ConfigureNodeSystem(system)
+
+	results := make([]ComputationResult, 6)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = engine.Compute(map[string]interface{}{})
+		}(i)
+	}
+	wg.Wait()
+
+	if maxRunning != 2 {
+		t.Errorf("concurrent computations - got: %v, want: %v", maxRunning, 2)
+	}
+	waited := 0
+	for _, result := range results {
+		if result.Error != nil {
+			t.Fatalf("computation error - got: %+v", result.Error)
+		}
+		if len(result.Trace) != 1 || result.Trace[0].Node != writeDatabase {
+			t.Fatalf("trace - got: %+v", result.Trace)
+		}
+		if result.Trace[0].Wait > 0 {
+			waited++
+		}
+	}
+	if waited < 4 {
+		t.Errorf("computations waiting for the bulkhead - got: %v, want: at least %v", waited, 4)
+	}
+}
+
+func Test_Bulkhead_WaitCancelled(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	system := NewNodeSystem()
+	system.AddNode(node)
+	system.ConfigureBulkhead(node, 1)
+	system.Activate()
+	system.nodesBulkheads[node].enter(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	cp, _ := NewComputation(system, NewContextWithoutData())
+	err := cp.ComputeContext(ctx)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error - got: %+v, want: %+v", err, context.DeadlineExceeded)
+	}
+	if len(cp.Trace) != 1 || cp.Trace[0].Wait < 10*time.Millisecond {
+		t.Errorf("trace - got: %+v", cp.Trace)
+	}
+}
+
+func Test_Bulkhead_SwapNodeSystem_midflight(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	writeDatabase, _ := NewActionNode("writeDatabase", func(c *Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	system := NewNodeSystem()
+	system.AddNode(writeDatabase)
+	system.ConfigureBulkhead(writeDatabase, 1)
+	system.Activate()
+	engine := NewEngine(SequentialComputation)
+	engine.ConfigureNodeSystem(system)
+
+	running := make(chan ComputationResult)
+	go func() {
+		running <- engine.Compute(map[string]interface{}{})
+	}()
+	<-started
+
+	clone := system.Clone()
+	clone.Activate()
+	engine.SwapNodeSystem(clone)
+	engine.ConfigureVariant("variant", clone)
+	engine.ConfigureRouting(PredicateRouting(func(data map[string]interface{}) bool {
+		return data["variant"] == true
+	}, "variant", ""))
+
+	for _, data := range []map[string]interface{}{{}, {"variant": true}} {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		result := engine.ComputeContext(ctx, data)
+		cancel()
+		if !errors.Is(result.Error, context.DeadlineExceeded) {
+			t.Errorf("error over the bulkhead of the swapped node system - got: %+v, want: %+v", result.Error, context.DeadlineExceeded)
+		}
+	}
+
+	close(release)
+	if result := <-running; result.Error != nil {
+		t.Errorf("running computation error - got: %+v", result.Error)
+	}
+}
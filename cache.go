@@ -0,0 +1,144 @@
+package hoff
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheCapacity is the default number of entries of the cache of a pure action node.
+	DefaultCacheCapacity = 1024
+	// DefaultCacheTTL is the default duration to keep an entry in the cache of a pure action node.
+	DefaultCacheTTL = 10 * time.Minute
+)
+
+// ContextWrites hold the values stored, and the keys deleted, in a context by a node computation.
+// The stored values are deep copies, never shared with a context.
+type ContextWrites struct {
+	Stored  map[string]interface{}
+	Deleted []string
+}
+
+// apply store a deep copy of the stored values, and delete the keys, in a context.
+func (w ContextWrites) apply(c *Context) {
+	for key, value := range w.Stored {
+		c.Store(key, deepCopyValue(value))
+	}
+	for _, key := range w.Deleted {
+		c.Delete(key)
+	}
+}
+
+// newContextWrites give the writes to go from a data (deep copied before the node computation)
+// to the data of a context.
+func newContextWrites(before map[string]interface{}, c *Context) ContextWrites {
+	writes := ContextWrites{
+		Stored: make(map[string]interface{}),
+	}
+	for key, value := range c.Data {
+		previous, found := before[key]
+		if !found || !reflect.DeepEqual(previous, value) {
+			writes.Stored[key] = deepCopyValue(value)
+		}
+	}
+	for key := range before {
+		if !c.HaveKey(key) {
+			writes.Deleted = append(writes.Deleted, key)
+		}
+	}
+	return writes
+}
+
+// Cache keep the context writes of pure nodes computations by cache key.
+type Cache interface {
+	// Get find the context writes of a cache key
+	Get(key string) (ContextWrites, bool)
+	// Set keep the context writes of a cache key
+	Set(key string, writes ContextWrites)
+}
+
+// CacheableNode is a Node whose computation can be replaced by the cached context writes
+// of a previous computation with the same cache key.
+type CacheableNode interface {
+	Node
+	// CacheKey give the cache key of a computation on a context, if the computation is cacheable
+	CacheKey(c *Context) (string, bool)
+	// Cache give the cache of the node computations
+	Cache() Cache
+}
+
+// LRUCache is an in-memory Cache who keep a limited number of entries during a limited duration,
+// and evict the least recently used entry when full.
+type LRUCache struct {
+	mutex    sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	writes    ContextWrites
+	expiresAt time.Time
+}
+
+// NewLRUCache create an in-memory cache with a capacity (at least 1), and a time to live for its entries.
+func NewLRUCache(capacity int, ttl time.Duration) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get find the context writes of a cache key, if not expired.
+func (c *LRUCache) Get(key string) (ContextWrites, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, found := c.entries[key]
+	if !found {
+		return ContextWrites{}, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return ContextWrites{}, false
+	}
+	c.order.MoveToFront(element)
+	return entry.writes, true
+}
+
+// Set keep the context writes of a cache key, and evict the least recently used entry when full.
+func (c *LRUCache) Set(key string, writes ContextWrites) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if element, found := c.entries[key]; found {
+		element.Value = &lruEntry{key: key, writes: writes, expiresAt: expiresAt}
+		c.order.MoveToFront(element)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, writes: writes, expiresAt: expiresAt})
+}
+
+// Len give the number of entries in the cache (expired or not).
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
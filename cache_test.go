@@ -0,0 +1,171 @@
+package hoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_LRUCache(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLRUCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	first := ContextWrites{Stored: map[string]interface{}{"key": 1}}
+	second := ContextWrites{Stored: map[string]interface{}{"key": 2}}
+	third := ContextWrites{Deleted: []string{"key"}}
+	cache.Set("first", first)
+	cache.Set("second", second)
+	cache.Get("first")
+	cache.Set("third", third)
+
+	testCases := []struct {
+		name           string
+		givenKey       string
+		givenDelay     time.Duration
+		expectedWrites ContextWrites
+		expectedFound  bool
+	}{
+		{
+			name:           "Get a recently used entry",
+			givenKey:       "first",
+			expectedWrites: first,
+			expectedFound:  true,
+		},
+		{
+			name:     "Don't get the least recently used entry",
+			givenKey: "second",
+		},
+		{
+			name:           "Get a new entry",
+			givenKey:       "third",
+			expectedWrites: third,
+			expectedFound:  true,
+		},
+		{
+			name:       "Don't get an expired entry",
+			givenKey:   "third",
+			givenDelay: time.Minute,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			now = now.Add(testCase.givenDelay)
+			writes, found := cache.Get(testCase.givenKey)
+
+			if found != testCase.expectedFound {
+				t.Errorf("found - got: %+v, want: %+v", found, testCase.expectedFound)
+			}
+			if !cmp.Equal(writes, testCase.expectedWrites) {
+				t.Errorf("writes - got: %+v, want: %+v", writes, testCase.expectedWrites)
+			}
+		})
+	}
+	if cache.Len() != 1 {
+		t.Errorf("length - got: %+v, want: %+v", cache.Len(), 1)
+	}
+}
+
+func Test_Computation_Compute_with_pure_node(t *testing.T) {
+	lookups := 0
+	lookupAction, _ := NewActionNode("lookupAction", func(c *Context) error {
+		lookups++
+		user, _ := c.Read("user")
+		c.Store("name", user.(string)+" name")
+		c.Delete("temporary")
+		return nil
+	})
+	lookupAction.ConfigurePure([]string{"user"}, nil)
+
+	ns := NewNodeSystem()
+	ns.AddNode(lookupAction)
+	ns.Activate()
+
+	testCases := []struct {
+		name            string
+		givenData       map[string]interface{}
+		expectedData    map[string]interface{}
+		expectedReport  map[Node]ComputeState
+		expectedLookups int
+	}{
+		{
+			name:            "Compute a pure node",
+			givenData:       map[string]interface{}{"user": "a", "temporary": true},
+			expectedData:    map[string]interface{}{"user": "a", "name": "a name"},
+			expectedReport:  map[Node]ComputeState{lookupAction: NewContinueComputeState()},
+			expectedLookups: 1,
+		},
+		{
+			name:            "Apply the cached writes of a pure node on the same inputs",
+			givenData:       map[string]interface{}{"user": "a", "temporary": true, "other": true},
+			expectedData:    map[string]interface{}{"user": "a", "name": "a name", "other": true},
+			expectedReport:  map[Node]ComputeState{lookupAction: NewCachedComputeState()},
+			expectedLookups: 1,
+		},
+		{
+			name:            "Compute a pure node on other inputs",
+			givenData:       map[string]interface{}{"user": "b"},
+			expectedData:    map[string]interface{}{"user": "b", "name": "b name"},
+			expectedReport:  map[Node]ComputeState{lookupAction: NewContinueComputeState()},
+			expectedLookups: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cp, _ := NewComputation(ns, NewContext(testCase.givenData))
+			err := cp.Compute()
+
+			if err != nil {
+				t.Errorf("error - got: %+v", err)
+			}
+			if !cmp.Equal(cp.Context.Data, testCase.expectedData) {
+				t.Errorf("data - got: %+v, want: %+v", cp.Context.Data, testCase.expectedData)
+			}
+			if !cmp.Equal(cp.Report, testCase.expectedReport, NodeComparator, errorComparator) {
+				t.Errorf("report - got: %+v, want: %+v", cp.Report, testCase.expectedReport)
+			}
+			if lookups != testCase.expectedLookups {
+				t.Errorf("lookups - got: %+v, want: %+v", lookups, testCase.expectedLookups)
+			}
+		})
+	}
+}
+
+func Test_Computation_Compute_with_pure_node_writing_nested_values(t *testing.T) {
+	tagAction, _ := NewActionNode("tagAction", func(c *Context) error {
+		order, _ := c.Read("order")
+		order.(map[string]interface{})["tag"] = "tagged"
+		return nil
+	})
+	tagAction.ConfigurePure([]string{"id"}, nil)
+
+	ns := NewNodeSystem()
+	ns.AddNode(tagAction)
+	ns.Activate()
+
+	compute := func() map[string]interface{} {
+		cp, _ := NewComputation(ns, NewContext(map[string]interface{}{"id": 1, "order": map[string]interface{}{}}))
+		if err := cp.Compute(); err != nil {
+			t.Fatalf("error - got: %+v", err)
+		}
+		return cp.Context.Data
+	}
+
+	expectedData := map[string]interface{}{"id": 1, "order": map[string]interface{}{"tag": "tagged"}}
+	computed := compute()
+	if !cmp.Equal(computed, expectedData) {
+		t.Errorf("computed data - got: %+v, want: %+v", computed, expectedData)
+	}
+	computed["order"].(map[string]interface{})["tag"] = "changed"
+
+	cached := compute()
+	if !cmp.Equal(cached, expectedData) {
+		t.Errorf("cached data - got: %+v, want: %+v", cached, expectedData)
+	}
+	cached["order"].(map[string]interface{})["tag"] = "changed"
+
+	if again := compute(); !cmp.Equal(again, expectedData) {
+		t.Errorf("cached data after change - got: %+v, want: %+v", again, expectedData)
+	}
+}
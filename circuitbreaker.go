@@ -0,0 +1,208 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of a node.
+type CircuitState string
+
+const (
+	// CircuitClosed let the computations of the node happen, and count their aborts.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fail fast the computations of the node, without computing it.
+	CircuitOpen = "open"
+	// CircuitHalfOpen let one computation of the node happen as a probe,
+	// to close the circuit on success, or to open it again on abort.
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitBreaker define when the circuit of a node open (after Failures aborts during a Window),
+// and how long it stay open before probing the node (OpenDuration).
+// While open, a decision node can continue on a Fallback branch instead of failing fast,
+// and each change of the circuit state is notified to OnStateChange (if any).
+type CircuitBreaker struct {
+	Failures      int
+	Window        time.Duration
+	OpenDuration  time.Duration
+	Fallback      *bool
+	OnStateChange func(node Node, from, to CircuitState)
+}
+
+// CircuitOpenError is the error of a node computation failing fast due to its open circuit.
+type CircuitOpenError struct {
+	Node Node
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("can't compute node with open circuit: %v", e.Node.ID())
+}
+
+// ConfigureCircuitBreaker protect the computations of a node by a circuit breaker into the system before activation.
+// The circuit breaker is shared by all computations of the activated node system, and of its clones, like the ones of an Engine.
+func (s *NodeSystem) ConfigureCircuitBreaker(n Node, cb CircuitBreaker) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't configure circuit breaker, node system is freeze due to activation")
+	}
+	if !s.haveNode(n) {
+		return false, fmt.Errorf("can't configure circuit breaker of undeclared node: %+v", n)
+	}
+	if cb.Failures < 1 {
+		return false, fmt.Errorf("can't configure circuit breaker without positive failures: %v", cb.Failures)
+	}
+	if cb.Window <= 0 {
+		return false, fmt.Errorf("can't configure circuit breaker without positive window: %v", cb.Window)
+	}
+	if cb.OpenDuration <= 0 {
+		return false, fmt.Errorf("can't configure circuit breaker without positive open duration: %v", cb.OpenDuration)
+	}
+	if cb.Fallback != nil && !n.DecideCapability() {
+		return false, fmt.Errorf("can't configure circuit breaker fallback branch on node without decide capability: %v", n.ID())
+	}
+	s.nodesCircuitBreakers[n] = newBreaker(cb)
+	return true, nil
+}
+
+// CircuitStateOfNode get the current state of the circuit breaker of a node
+func (s *NodeSystem) CircuitStateOfNode(n Node) (CircuitState, bool) {
+	breaker, found := s.nodesCircuitBreakers[n]
+	if !found {
+		return "", false
+	}
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	return breaker.state, true
+}
+
+// breaker is the circuit of a circuit breaker, shared by the versions of a node (across clones, and replacements).
+// Each change of the circuit state start a new generation, for a computation allowed in a previous generation
+// (like before the circuit opening) to count for nothing.
+type breaker struct {
+	mutex      sync.Mutex
+	config     CircuitBreaker
+	state      CircuitState
+	generation uint64
+	failures   []time.Time
+	openedAt   time.Time
+	probing    bool
+	now        func() time.Time
+}
+
+func newBreaker(cb CircuitBreaker) *breaker {
+	return &breaker{
+		config: cb,
+		state:  CircuitClosed,
+		now:    time.Now,
+	}
+}
+
+// allow tell if the node can be computed, and give the generation of the allowed computation,
+// the open circuit becoming half-open to probe the node once its open duration is over.
+func (b *breaker) allow(node Node) (uint64, bool) {
+	b.mutex.Lock()
+	from := b.state
+	allowed := true
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.config.OpenDuration {
+			allowed = false
+			break
+		}
+		b.state = CircuitHalfOpen
+		b.generation++
+		b.probing = true
+	case CircuitHalfOpen:
+		if b.probing {
+			allowed = false
+			break
+		}
+		b.probing = true
+	}
+	to := b.state
+	generation := b.generation
+	b.mutex.Unlock()
+
+	b.notify(node, from, to)
+	return generation, allowed
+}
+
+// record the compute state of an allowed node computation, a rejected, or cancelled, computation counting for nothing,
+// like a computation allowed in a previous generation (only the probe counting while half-open).
+func (b *breaker) record(node Node, generation uint64, state ComputeState, cancelled bool) {
+	b.mutex.Lock()
+	if generation != b.generation {
+		b.mutex.Unlock()
+		return
+	}
+	from := b.state
+	now := b.now()
+	neutral := cancelled || state.Value == RejectState
+	failed := !neutral && state.Value == AbortState
+	switch b.state {
+	case CircuitClosed:
+		if failed {
+			failures := make([]time.Time, 0, len(b.failures)+1)
+			for _, failure := range b.failures {
+				if now.Sub(failure) < b.config.Window {
+					failures = append(failures, failure)
+				}
+			}
+			b.failures = append(failures, now)
+			if len(b.failures) >= b.config.Failures {
+				b.open(now)
+			}
+		}
+	case CircuitHalfOpen:
+		b.probing = false
+		if failed {
+			b.open(now)
+		} else if !neutral {
+			b.state = CircuitClosed
+			b.generation++
+			b.failures = nil
+		}
+	}
+	to := b.state
+	b.mutex.Unlock()
+
+	b.notify(node, from, to)
+}
+
+func (b *breaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.generation++
+	b.openedAt = now
+	b.failures = nil
+}
+
+func (b *breaker) notify(node Node, from, to CircuitState) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(node, from, to)
+	}
+}
+
+// failFast give the compute state of a node who can't be computed due to its open circuit.
+func (b *breaker) failFast(node Node) ComputeState {
+	if b.config.Fallback != nil {
+		return NewContinueOnBranchComputeState(*b.config.Fallback)
+	}
+	return NewRejectComputeState(CircuitOpenError{Node: node})
+}
+
+// guardedCompute compute a node under its limits, and its circuit breaker.
+func (cp *Computation) guardedCompute(node Node) ComputeState {
+	breaker, found := cp.System.nodesCircuitBreakers[node]
+	if !found {
+		return cp.limitedCompute(node)
+	}
+	generation, allowed := breaker.allow(node)
+	if !allowed {
+		return breaker.failFast(node)
+	}
+	state := cp.limitedCompute(node)
+	breaker.record(node, generation, state, cp.ctx.Err() != nil)
+	return state
+}
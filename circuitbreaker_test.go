@@ -0,0 +1,329 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ConfigureCircuitBreaker(t *testing.T) {
+	action, _ := NewActionNode("action", func(*Context) error { return nil })
+	decision, _ := NewDecisionNode("decision", func(*Context) (bool, error) { return true, nil })
+	activatedSystem := NewNodeSystem()
+	activatedSystem.AddNode(action)
+	activatedSystem.Activate()
+	valid := CircuitBreaker{Failures: 3, Window: time.Minute, OpenDuration: time.Minute}
+	fallback := valid
+	fallback.Fallback = boolPointer(false)
+
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenNode     Node
+		givenBreaker  CircuitBreaker
+		expectedState CircuitState
+		expectedError error
+	}{
+		{
+			name:          "Can configure a circuit breaker",
+			givenNode:     action,
+			givenBreaker:  valid,
+			expectedState: CircuitClosed,
+		},
+		{
+			name:          "Can configure a circuit breaker with fallback branch on a decision node",
+			givenNode:     decision,
+			givenBreaker:  fallback,
+			expectedState: CircuitClosed,
+		},
+		{
+			name:          "Can't configure a circuit breaker on an activated node system",
+			givenSystem:   activatedSystem,
+			givenNode:     action,
+			givenBreaker:  valid,
+			expectedError: errors.New("can't configure circuit breaker, node system is freeze due to activation"),
+		},
+		{
+			name:          "Can't configure a circuit breaker of an undeclared node",
+			givenNode:     &SomeNode{},
+			givenBreaker:  valid,
+			expectedError: errors.New("can't configure circuit breaker of undeclared node: &{}"),
+		},
+		{
+			name:          "Can't configure a circuit breaker without failures",
+			givenNode:     action,
+			givenBreaker:  CircuitBreaker{Window: time.Minute, OpenDuration: time.Minute},
+			expectedError: errors.New("can't configure circuit breaker without positive failures: 0"),
+		},
+		{
+			name:          "Can't configure a circuit breaker without window",
+			givenNode:     action,
+			givenBreaker:  CircuitBreaker{Failures: 3, OpenDuration: time.Minute},
+			expectedError: errors.New("can't configure circuit breaker without positive window: 0s"),
+		},
+		{
+			name:          "Can't configure a circuit breaker without open duration",
+			givenNode:     action,
+			givenBreaker:  CircuitBreaker{Failures: 3, Window: time.Minute},
+			expectedError: errors.New("can't configure circuit breaker without positive open duration: 0s"),
+		},
+		{
+			name:          "Can't configure a circuit breaker with fallback branch on an action node",
+			givenNode:     action,
+			givenBreaker:  fallback,
+			expectedError: errors.New("can't configure circuit breaker fallback branch on node without decide capability: action"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			system := testCase.givenSystem
+			if system == nil {
+				system = NewNodeSystem()
+				system.AddNode(action)
+				system.AddNode(decision)
+			}
+			ok, err := system.ConfigureCircuitBreaker(testCase.givenNode, testCase.givenBreaker)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if ok != (testCase.expectedError == nil) {
+				t.Errorf("ok - got: %+v, want: %+v", ok, testCase.expectedError == nil)
+			}
+			state, _ := system.CircuitStateOfNode(testCase.givenNode)
+			if state != testCase.expectedState {
+				t.Errorf("state - got: %+v, want: %+v", state, testCase.expectedState)
+			}
+		})
+	}
+}
+
+func Test_CircuitBreaker_Engine(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	partnerDown := errors.New("partner is down")
+	calls := 0
+	callPartner, _ := NewActionNode("callPartner", func(c *Context) error {
+		calls++
+		if c.HaveKey("down") {
+			return partnerDown
+		}
+		return nil
+	})
+	events := make([]string, 0)
+	system := NewNodeSystem()
+	system.AddNode(callPartner)
+	system.ConfigureCircuitBreaker(callPartner, CircuitBreaker{
+		Failures:     2,
+		Window:       time.Minute,
+		OpenDuration: 30 * time.Second,
+		OnStateChange: func(node Node, from, to CircuitState) {
+			events = append(events, fmt.Sprintf("%v: %v -> %v", node.ID(), from, to))
+		},
+	})
+	system.nodesCircuitBreakers[callPartner].now = func() time.Time { return now }
+	system.Activate()
+	engine := NewEngine(SequentialComputation)
+	engine.ConfigureNodeSystem(system)
+
+	down := map[string]interface{}{"down": true}
+	testCases := []struct {
+		name            string
+		givenDelay      time.Duration
+		givenData       map[string]interface{}
+		expectedState   ComputeState
+		expectedCalls   int
+		expectedCircuit CircuitState
+	}{
+		{
+			name:            "Count an abort",
+			givenData:       down,
+			expectedState:   NewAbortComputeState(partnerDown),
+			expectedCalls:   1,
+			expectedCircuit: CircuitClosed,
+		},
+		{
+			name:            "Count an abort after the window of the previous one",
+			givenDelay:      2 * time.Minute,
+			givenData:       down,
+			expectedState:   NewAbortComputeState(partnerDown),
+			expectedCalls:   2,
+			expectedCircuit: CircuitClosed,
+		},
+		{
+			name:            "Open the circuit after enough aborts during the window",
+			givenDelay:      10 * time.Second,
+			givenData:       down,
+			expectedState:   NewAbortComputeState(partnerDown),
+			expectedCalls:   3,
+			expectedCircuit: CircuitOpen,
+		},
+		{
+			name:            "Fail fast with open circuit",
+			expectedState:   NewRejectComputeState(CircuitOpenError{Node: callPartner}),
+			expectedCalls:   3,
+			expectedCircuit: CircuitOpen,
+		},
+		{
+			name:            "Open again the circuit after a failed probe",
+			givenDelay:      30 * time.Second,
+			givenData:       down,
+			expectedState:   NewAbortComputeState(partnerDown),
+			expectedCalls:   4,
+			expectedCircuit: CircuitOpen,
+		},
+		{
+			name:            "Close the circuit after a successful probe",
+			givenDelay:      30 * time.Second,
+			expectedState:   NewContinueComputeState(),
+			expectedCalls:   5,
+			expectedCircuit: CircuitClosed,
+		},
+	}
+	for _, testCase := range testCases {
+		now = now.Add(testCase.givenDelay)
+		data := make(map[string]interface{})
+		for key, value := range testCase.givenData {
+			data[key] = value
+		}
+		result := engine.Compute(data)
+
+		if state := result.Report[callPartner]; !cmp.Equal(state, testCase.expectedState, errorComparator) {
+			t.Errorf("%v: state - got: %+v, want: %+v", testCase.name, state, testCase.expectedState)
+		}
+		if calls != testCase.expectedCalls {
+			t.Errorf("%v: calls - got: %v, want: %v", testCase.name, calls, testCase.expectedCalls)
+		}
+		if circuit, _ := system.CircuitStateOfNode(callPartner); circuit != testCase.expectedCircuit {
+			t.Errorf("%v: circuit - got: %v, want: %v", testCase.name, circuit, testCase.expectedCircuit)
+		}
+	}
+
+	expectedEvents := []string{
+		"callPartner: closed -> open",
+		"callPartner: open -> half-open",
+		"callPartner: half-open -> open",
+		"callPartner: open -> half-open",
+		"callPartner: half-open -> closed",
+	}
+	if !cmp.Equal(events, expectedEvents) {
+		t.Errorf("events - got: %v, want: %v", events, expectedEvents)
+	}
+}
+
+func Test_CircuitBreaker_Fallback(t *testing.T) {
+	partnerDown := errors.New("partner is down")
+	isEligible, _ := NewDecisionNode("isEligible", func(*Context) (bool, error) {
+		return false, partnerDown
+	})
+	manualReview, _ := NewActionNode("manualReview", func(c *Context) error {
+		c.Store("review", true)
+		return nil
+	})
+	approve, _ := NewActionNode("approve", func(*Context) error { return nil })
+	system := NewNodeSystem()
+	system.AddNode(isEligible)
+	system.AddNode(approve)
+	system.AddNode(manualReview)
+	system.AddLinkOnBranch(isEligible, approve, true)
+	system.AddLinkOnBranch(isEligible, manualReview, false)
+	system.ConfigureCircuitBreaker(isEligible, CircuitBreaker{Failures: 1, Window: time.Minute, OpenDuration: time.Minute, Fallback: boolPointer(false)})
+	system.Activate()
+	engine := NewEngine(SequentialComputation)
+	engine.ConfigureNodeSystem(system)
+
+	if result := engine.Compute(map[string]interface{}{}); !cmp.Equal(result.Error, partnerDown, errorComparator) {
+		t.Errorf("first error - got: %+v, want: %+v", result.Error, partnerDown)
+	}
+	result := engine.Compute(map[string]interface{}{})
+	if result.Error != nil {
+		t.Fatalf("error - got: %+v", result.Error)
+	}
+	expectedReport := map[Node]ComputeState{
+		isEligible:   NewContinueOnBranchComputeState(false),
+		approve:      NewSkipComputeState(),
+		manualReview: NewContinueComputeState(),
+	}
+	if !cmp.Equal(result.Report, expectedReport, NodeComparator, errorComparator) {
+		t.Errorf("report - got: %+v, want: %+v", result.Report, expectedReport)
+	}
+}
+
+func Test_CircuitBreaker_SwapNodeSystem(t *testing.T) {
+	callPartner, _ := NewActionNode("callPartner", func(c *Context) error {
+		return errors.New("partner is down")
+	})
+	system := NewNodeSystem()
+	system.AddNode(callPartner)
+	system.ConfigureCircuitBreaker(callPartner, CircuitBreaker{Failures: 1, Window: time.Minute, OpenDuration: time.Minute})
+	system.Activate()
+	engine := NewEngine(SequentialComputation)
+	engine.ConfigureNodeSystem(system)
+	engine.Compute(map[string]interface{}{})
+
+	calls := 0
+	fixedPartner, _ := NewActionNode("callPartner", func(c *Context) error {
+		calls++
+		return nil
+	})
+	clone := system.Clone()
+	clone.ReplaceNode(callPartner, fixedPartner)
+	clone.Activate()
+	engine.SwapNodeSystem(clone)
+
+	if state, _ := clone.CircuitStateOfNode(fixedPartner); state != CircuitOpen {
+		t.Errorf("circuit of the swapped node system - got: %v, want: %v", state, CircuitOpen)
+	}
+	result := engine.Compute(map[string]interface{}{})
+	expectedError := CircuitOpenError{Node: fixedPartner}
+	if !cmp.Equal(result.Error, expectedError, errorComparator) || calls != 0 {
+		t.Errorf("error - got: %+v (calls: %v), want: %+v", result.Error, calls, expectedError)
+	}
+}
+
+func Test_CircuitBreaker_overlapping_computations(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	now := time.Now()
+	var changes []string
+	b := newBreaker(CircuitBreaker{Failures: 1, Window: time.Minute, OpenDuration: time.Minute, OnStateChange: func(node Node, from, to CircuitState) {
+		changes = append(changes, fmt.Sprintf("%v->%v", from, to))
+	}})
+	b.now = func() time.Time { return now }
+	failure := NewAbortComputeState(errors.New("failure"))
+
+	slowSuccess, _ := b.allow(node)
+	slowNeutral, _ := b.allow(node)
+	slowCancelled, _ := b.allow(node)
+	failing, _ := b.allow(node)
+	b.record(node, failing, failure, false)
+	now = now.Add(time.Minute)
+	probe, allowed := b.allow(node)
+	if !allowed {
+		t.Fatal("probe must be allowed once the open duration is over")
+	}
+
+	b.record(node, slowSuccess, NewContinueComputeState(), false)
+	b.record(node, slowNeutral, NewRejectComputeState(errors.New("rejected")), false)
+	b.record(node, slowCancelled, failure, true)
+	if b.state != CircuitHalfOpen {
+		t.Errorf("state after stale results - got: %v, want: %v", b.state, CircuitHalfOpen)
+	}
+	if _, allowed := b.allow(node); allowed {
+		t.Error("second probe must not be allowed while the first probe is running")
+	}
+
+	b.record(node, probe, NewContinueComputeState(), false)
+	if b.state != CircuitClosed {
+		t.Errorf("state after probe - got: %v, want: %v", b.state, CircuitClosed)
+	}
+	b.record(node, failing, failure, false)
+	if b.state != CircuitClosed {
+		t.Errorf("state after stale failure - got: %v, want: %v", b.state, CircuitClosed)
+	}
+	expectedChanges := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if !cmp.Equal(changes, expectedChanges) {
+		t.Errorf("changes - got: %v, want: %v", changes, expectedChanges)
+	}
+}
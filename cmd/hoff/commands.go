@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/rlespinasse/hoff"
+)
+
+// pluginsFlag collect the paths of the go plugins to load.
+type pluginsFlag []string
+
+func (p *pluginsFlag) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *pluginsFlag) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// decisionsFlag collect the forced branches of decision nodes (as id=true, or id=false).
+type decisionsFlag map[string]bool
+
+func (d decisionsFlag) String() string {
+	decisions := make([]string, 0, len(d))
+	for id, branch := range d {
+		decisions = append(decisions, fmt.Sprintf("%v=%v", id, branch))
+	}
+	return strings.Join(decisions, ",")
+}
+
+func (d decisionsFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("can't have decision without branch: %v", value)
+	}
+	branch, err := strconv.ParseBool(parts[1])
+	if err != nil {
+		return fmt.Errorf("can't have decision with unknown branch: %v", value)
+	}
+	d[parts[0]] = branch
+	return nil
+}
+
+// workflowCommand parse the common flags of a command, and load its workflow file.
+type workflowCommand struct {
+	flags   *flag.FlagSet
+	plugins pluginsFlag
+	stderr  io.Writer
+}
+
+func newWorkflowCommand(name string, stderr io.Writer) *workflowCommand {
+	cmd := &workflowCommand{
+		flags:  flag.NewFlagSet(name, flag.ContinueOnError),
+		stderr: stderr,
+	}
+	cmd.flags.SetOutput(stderr)
+	cmd.flags.Var(&cmd.plugins, "plugin", "go plugin exporting Actions, and Decisions functions (repeatable)")
+	return cmd
+}
+
+// load parse the arguments, and load the workflow file.
+// Print the errors, and give the exit code to use, on failure.
+func (cmd *workflowCommand) load(args []string) (*loadedWorkflow, int) {
+	if err := cmd.flags.Parse(args); err != nil {
+		return nil, 2
+	}
+	if cmd.flags.NArg() != 1 {
+		fmt.Fprintf(cmd.stderr, "hoff %v: need one workflow file\n", cmd.flags.Name())
+		return nil, 2
+	}
+
+	reg := newBuiltinRegistry()
+	for _, path := range cmd.plugins {
+		if err := reg.loadPlugin(path); err != nil {
+			fmt.Fprintf(cmd.stderr, "hoff %v: %v\n", cmd.flags.Name(), err)
+			return nil, 1
+		}
+	}
+	lw, errs := loadWorkflow(cmd.flags.Arg(0), reg)
+	if errs != nil {
+		printErrors(cmd.stderr, errs)
+		return nil, 1
+	}
+	return lw, 0
+}
+
+// loadActivated load the workflow file, and activate its node system.
+func (cmd *workflowCommand) loadActivated(args []string) (*loadedWorkflow, int) {
+	lw, code := cmd.load(args)
+	if lw == nil {
+		return nil, code
+	}
+	if errs := lw.validate(); len(errs) > 0 {
+		printErrors(cmd.stderr, errs)
+		return nil, 1
+	}
+	lw.system.Activate()
+	return lw, 0
+}
+
+// readInput read the JSON file of the input data, or give empty data without file.
+// Print the error on failure.
+func (cmd *workflowCommand) readInput(path string) (map[string]interface{}, bool) {
+	data := make(map[string]interface{})
+	if path == "" {
+		return data, true
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(cmd.stderr, "hoff %v: %v\n", cmd.flags.Name(), err)
+		return nil, false
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		fmt.Fprintf(cmd.stderr, "hoff %v: %v: %v\n", cmd.flags.Name(), path, err)
+		return nil, false
+	}
+	return data, true
+}
+
+func validateCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := newWorkflowCommand("validate", stderr)
+	lw, code := cmd.load(args)
+	if lw == nil {
+		return code
+	}
+	if errs := lw.validate(); len(errs) > 0 {
+		printErrors(stdout, errs)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%v: valid\n", lw.path)
+	return 0
+}
+
+func graphCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := newWorkflowCommand("graph", stderr)
+	format := cmd.flags.String("format", "dot", "output format: dot, or mermaid")
+	lw, code := cmd.load(args)
+	if lw == nil {
+		return code
+	}
+	switch *format {
+	case "dot":
+		fmt.Fprint(stdout, lw.system.DOT())
+	case "mermaid":
+		fmt.Fprint(stdout, lw.system.Mermaid())
+	default:
+		fmt.Fprintf(stderr, "hoff graph: unknown format '%v'\n", *format)
+		return 2
+	}
+	return 0
+}
+
+func planCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := newWorkflowCommand("plan", stderr)
+	lw, code := cmd.loadActivated(args)
+	if lw == nil {
+		return code
+	}
+	plan, err := lw.system.ExecutionPlan()
+	if err != nil {
+		fmt.Fprintf(stderr, "hoff plan: %v\n", err)
+		return 1
+	}
+	for level, nodes := range plan.Levels {
+		for _, node := range nodes {
+			dependencies := plan.Dependencies(node)
+			if len(dependencies) == 0 {
+				fmt.Fprintf(stdout, "%v: %v\n", level, node.ID())
+			} else {
+				fmt.Fprintf(stdout, "%v: %v (after %v)\n", level, node.ID(), joinNodes(dependencies))
+			}
+		}
+	}
+	return 0
+}
+
+func runCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := newWorkflowCommand("run", stderr)
+	input := cmd.flags.String("input", "", "JSON file of the input data (empty data if missing)")
+	dryRun := cmd.flags.Bool("dry-run", false, "walk the workflow without running the nodes, using the forced decisions")
+	decisions := make(decisionsFlag)
+	cmd.flags.Var(decisions, "decide", "forced branch of a decision node during a dry run, as id=true or id=false (repeatable)")
+	lw, code := cmd.loadActivated(args)
+	if lw == nil {
+		return code
+	}
+	if len(decisions) > 0 && !*dryRun {
+		fmt.Fprintln(stderr, "hoff run: can't force decisions without dry run")
+		return 2
+	}
+
+	data, ok := cmd.readInput(*input)
+	if !ok {
+		return 1
+	}
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(lw.system)
+	var result hoff.ComputationResult
+	if *dryRun {
+		result = eng.DryRun(data, hoff.ForcedDecisions(decisions))
+	} else {
+		result = eng.Compute(data)
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(stderr, "hoff run: %v\n", err)
+		return 1
+	}
+	if result.Error != nil {
+		return 1
+	}
+	return 0
+}
+
+func printErrors(w io.Writer, errs []error) {
+	for _, err := range errs {
+		fmt.Fprintln(w, err)
+	}
+}
+
+func joinNodes(nodes []hoff.Node) string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID())
+	}
+	return strings.Join(ids, ", ")
+}
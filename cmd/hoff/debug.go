@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rlespinasse/hoff"
+)
+
+const debugHelp = `commands:
+  step, s                 compute (or skip) the next node, and print its context changes
+  continue, c             compute until a breakpoint, or the end of the computation
+  break <node>            pause before computing a node
+  watch <key>             pause after a node computation writing a context key
+  force <node> true|false make a decision node take a branch without running it
+  set <key> <json>        store a JSON value in the context
+  unset <key>             delete a key from the context
+  context                 print the context
+  frontier                print the pending nodes
+  help                    print the commands
+  quit, q                 stop the debug session`
+
+func debugCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := newWorkflowCommand("debug", stderr)
+	input := cmd.flags.String("input", "", "JSON file of the input data (empty data if missing)")
+	lw, code := cmd.loadActivated(args)
+	if lw == nil {
+		return code
+	}
+	data, ok := cmd.readInput(*input)
+	if !ok {
+		return 1
+	}
+	cp, err := hoff.NewComputation(lw.system, hoff.NewContext(data))
+	if err != nil {
+		fmt.Fprintf(stderr, "hoff debug: %v\n", err)
+		return 1
+	}
+
+	session := &debugSession{
+		workflow:    lw,
+		computation: cp,
+		out:         stdout,
+	}
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stdout, "(hoff) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stdout)
+			break
+		}
+		if !session.execute(strings.Fields(scanner.Text())) {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "hoff debug: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// debugSession run the commands of a debug session on a stepping computation.
+type debugSession struct {
+	workflow    *loadedWorkflow
+	computation *hoff.Computation
+	out         io.Writer
+}
+
+// execute run a command, and tell if the debug session continue.
+func (s *debugSession) execute(fields []string) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "step", "s":
+		s.step()
+	case "continue", "c":
+		s.resume()
+	case "break":
+		if !s.arguments(args, 1, "break <node>") {
+			break
+		}
+		if node, ok := s.node(args[0]); ok {
+			s.computation.AddBreakpoint(node)
+			fmt.Fprintf(s.out, "breakpoint on %v\n", node.ID())
+		}
+	case "watch":
+		if s.arguments(args, 1, "watch <key>") {
+			s.computation.AddKeyBreakpoint(args[0])
+			fmt.Fprintf(s.out, "watching %v\n", args[0])
+		}
+	case "force":
+		s.force(args)
+	case "set":
+		s.set(args)
+	case "unset":
+		if s.arguments(args, 1, "unset <key>") {
+			s.computation.Context.Delete(args[0])
+			fmt.Fprintf(s.out, "- %v\n", args[0])
+		}
+	case "context":
+		s.printContext()
+	case "frontier":
+		s.printFrontier()
+	case "help":
+		fmt.Fprintln(s.out, debugHelp)
+	case "quit", "q":
+		return false
+	default:
+		fmt.Fprintf(s.out, "unknown command '%v' (type help for the commands)\n", command)
+	}
+	return true
+}
+
+func (s *debugSession) step() {
+	if s.computation.IsFinished() {
+		fmt.Fprintln(s.out, "finished")
+		return
+	}
+	step, err := s.computation.Step()
+	if step.Node == nil && err == nil {
+		fmt.Fprintln(s.out, "finished")
+		return
+	}
+	s.printStep(step)
+	if err != nil {
+		fmt.Fprintf(s.out, "aborted: %v\n", err)
+	}
+}
+
+func (s *debugSession) resume() {
+	if s.computation.IsFinished() {
+		fmt.Fprintln(s.out, "finished")
+		return
+	}
+	breakpoint, err := s.computation.Continue()
+	switch {
+	case err != nil:
+		fmt.Fprintf(s.out, "aborted: %v\n", err)
+	case breakpoint == nil:
+		fmt.Fprintln(s.out, "finished")
+	case breakpoint.Step != nil:
+		s.printStep(*breakpoint.Step)
+		fmt.Fprintf(s.out, "paused: %v written\n", breakpoint.Key)
+	default:
+		fmt.Fprintf(s.out, "paused before %v\n", breakpoint.Node.ID())
+	}
+}
+
+func (s *debugSession) force(args []string) {
+	if !s.arguments(args, 2, "force <node> true|false") {
+		return
+	}
+	node, ok := s.node(args[0])
+	if !ok {
+		return
+	}
+	branch, err := strconv.ParseBool(args[1])
+	if err != nil {
+		fmt.Fprintf(s.out, "can't force unknown branch: %v\n", args[1])
+		return
+	}
+	if err := s.computation.ForceDecision(node, branch); err != nil {
+		fmt.Fprintln(s.out, err)
+		return
+	}
+	fmt.Fprintf(s.out, "%v forced on %v\n", node.ID(), branch)
+}
+
+func (s *debugSession) set(args []string) {
+	if len(args) < 2 {
+		fmt.Fprintln(s.out, "usage: set <key> <json>")
+		return
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.Join(args[1:], " ")), &value); err != nil {
+		fmt.Fprintf(s.out, "can't set %v: %v\n", args[0], err)
+		return
+	}
+	s.computation.Context.Store(args[0], value)
+	fmt.Fprintf(s.out, "+ %v = %v\n", args[0], jsonText(value))
+}
+
+// node give the node of an identifier, or print that it's unknown.
+func (s *debugSession) node(id string) (hoff.Node, bool) {
+	node, found := s.workflow.nodes[id]
+	if !found {
+		fmt.Fprintf(s.out, "unknown node '%v'\n", id)
+		return nil, false
+	}
+	return node, true
+}
+
+// arguments check the count of arguments of a command, or print its usage.
+func (s *debugSession) arguments(args []string, count int, usage string) bool {
+	if len(args) != count {
+		fmt.Fprintf(s.out, "usage: %v\n", usage)
+		return false
+	}
+	return true
+}
+
+func (s *debugSession) printStep(step hoff.Step) {
+	fmt.Fprintf(s.out, "%v: %v\n", step.Node.ID(), step.State)
+	keys := make([]string, 0, len(step.Writes.Stored))
+	for key := range step.Writes.Stored {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(s.out, "+ %v = %v\n", key, jsonText(step.Writes.Stored[key]))
+	}
+	deleted := append(make([]string, 0, len(step.Writes.Deleted)), step.Writes.Deleted...)
+	sort.Strings(deleted)
+	for _, key := range deleted {
+		fmt.Fprintf(s.out, "- %v\n", key)
+	}
+}
+
+func (s *debugSession) printContext() {
+	keys := make([]string, 0, len(s.computation.Context.Data))
+	for key := range s.computation.Context.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(s.out, "%v = %v\n", key, jsonText(s.computation.Context.Data[key]))
+	}
+}
+
+func (s *debugSession) printFrontier() {
+	frontier := s.computation.Frontier()
+	if len(frontier) > 0 {
+		fmt.Fprintln(s.out, joinNodes(frontier))
+		return
+	}
+	fmt.Fprintln(s.out, "(none)")
+}
+
+func jsonText(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(content)
+}
@@ -0,0 +1,78 @@
+/*
+Command hoff validate, render, plan, run, and debug declarative workflow files.
+
+Usage:
+
+	hoff validate [-plugin file.so] workflow.json
+	hoff graph [-plugin file.so] [-format dot|mermaid] workflow.json
+	hoff plan [-plugin file.so] workflow.json
+	hoff run [-plugin file.so] [-input data.json] [-dry-run [-decide id=true|false ...]] workflow.json
+	hoff debug [-plugin file.so] [-input data.json] workflow.json
+
+A workflow file define nodes using built-in functions (or functions of go plugins), and links between them:
+
+	{
+	  "nodes": [
+	    {"name": "hasKey", "type": "decision", "func": "has_key", "args": {"key": "key"}},
+	    {"name": "found", "type": "action", "func": "store", "args": {"key": "found", "value": true}},
+	    {"name": "missing", "type": "action", "func": "fail", "args": {"message": "missing key"}}
+	  ],
+	  "links": [
+	    {"from": "hasKey", "to": "found", "branch": true},
+	    {"from": "hasKey", "to": "missing", "branch": false}
+	  ]
+	}
+
+A dry run walk the workflow without running its nodes, each decision node taking its forced branch.
+
+A debug session read commands from the standard input to step through the computation node by node,
+print the context changes of each node, edit the context, and force the branch of decision nodes
+(type help in the session for the commands).
+
+The built-in action functions are noop, store (key, value), copy (from, to), delete (key), and fail (message).
+The built-in decision functions are has_key (key), and equals (key, value).
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	commands := map[string]func([]string, io.Reader, io.Writer, io.Writer) int{
+		"validate": validateCommand,
+		"graph":    graphCommand,
+		"plan":     planCommand,
+		"run":      runCommand,
+		"debug":    debugCommand,
+	}
+	command, found := commands[args[0]]
+	if !found {
+		fmt.Fprintf(stderr, "hoff: unknown command '%v'\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return command(args[1:], stdin, stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `usage: hoff <command> [flags] workflow.json
+
+commands:
+  validate  check the workflow and print its errors with their locations
+  graph     render the workflow as a DOT, or Mermaid, graph
+  plan      print the activation order of the workflow nodes
+  run       compute a JSON input against the workflow and print the result as JSON
+  debug     step interactively through the computation of a JSON input against the workflow`)
+}
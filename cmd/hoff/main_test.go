@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rlespinasse/hoff"
+)
+
+func Test_run(t *testing.T) {
+	testCases := []struct {
+		name           string
+		givenArgs      []string
+		givenStdin     string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{
+			name:           "Can validate a valid workflow",
+			givenArgs:      []string{"validate", "testdata/has_key.json"},
+			expectedStdout: "testdata/has_key.json: valid\n",
+		},
+		{
+			name:         "Can validate an invalid workflow with locations",
+			givenArgs:    []string{"validate", "testdata/invalid.json"},
+			expectedCode: 1,
+			expectedStdout: "testdata/invalid.json: nodes[3]: can't have decision node without link from it: d4\n" +
+				"testdata/invalid.json: links[1],links[2]: Can't have cycle in links between nodes: [{from:'a2' to:'a3'} {from:'a3' to:'a2'}]\n" +
+				"testdata/invalid.json: nodes[1]: can't have multiple links (2) to the same node: a2 without join mode\n",
+		},
+		{
+			name:         "Can't load a workflow with unknown definitions",
+			givenArgs:    []string{"validate", "testdata/unknown.json"},
+			expectedCode: 1,
+			expectedStderr: "testdata/unknown.json: nodes[0]: can't have unknown action function 'unknown'\n" +
+				"testdata/unknown.json: nodes[1]: can't have missing 'key' argument\n" +
+				"testdata/unknown.json: nodes[2]: can't have unknown join mode 'xor'\n" +
+				"testdata/unknown.json: links[0]: can't have unknown node 'a4' as 'to'\n",
+		},
+		{
+			name:      "Can render a workflow as DOT",
+			givenArgs: []string{"graph", "testdata/has_key.json"},
+			expectedStdout: `digraph {
+	"hasKey" [label="hasKey" shape=diamond];
+	"found" [label="found" shape=box];
+	"missing" [label="missing" shape=box];
+	"hasKey" -> "found" [label="true"];
+	"hasKey" -> "missing" [label="false"];
+}
+`,
+		},
+		{
+			name:           "Can plan a workflow",
+			givenArgs:      []string{"plan", "testdata/has_key.json"},
+			expectedStdout: "0: hasKey\n1: found (after hasKey)\n1: missing (after hasKey)\n",
+		},
+		{
+			name:      "Can run a workflow",
+			givenArgs: []string{"run", "-input", "testdata/has_key_input.json", "testdata/has_key.json"},
+			expectedStdout: `{
+  "data": {
+    "found": true,
+    "key": "value"
+  },
+  "report": {
+    "found": {
+      "state": "Continue"
+    },
+    "hasKey": {
+      "state": "Continue",
+      "branch": true
+    },
+    "missing": {
+      "state": "Skip"
+    }
+  },
+  "path": [
+    "hasKey",
+    "found"
+  ],
+  "version": 1
+}
+`,
+		},
+		{
+			name:      "Can dry run a workflow with forced decisions",
+			givenArgs: []string{"run", "-dry-run", "-decide", "hasKey=false", "testdata/has_key.json"},
+			expectedStdout: `{
+  "data": {},
+  "report": {
+    "found": {
+      "state": "Skip"
+    },
+    "hasKey": {
+      "state": "Continue",
+      "branch": false
+    },
+    "missing": {
+      "state": "Continue"
+    }
+  },
+  "path": [
+    "hasKey",
+    "missing"
+  ],
+  "version": 1
+}
+`,
+		},
+		{
+			name:           "Can't force decisions without dry run",
+			givenArgs:      []string{"run", "-decide", "hasKey=false", "testdata/has_key.json"},
+			expectedCode:   2,
+			expectedStderr: "hoff run: can't force decisions without dry run\n",
+		},
+		{
+			name:       "Can debug a workflow step by step",
+			givenArgs:  []string{"debug", "-input", "testdata/has_key_input.json", "testdata/has_key.json"},
+			givenStdin: "frontier\ns\ns\ncontext\ns\ns\nquit\n",
+			expectedStdout: "(hoff) hasKey\n" +
+				"(hoff) hasKey: 'Continue on true'\n" +
+				"(hoff) found: 'Continue'\n" +
+				"+ found = true\n" +
+				"(hoff) found = true\n" +
+				"key = \"value\"\n" +
+				"(hoff) missing: 'Skip'\n" +
+				"(hoff) finished\n" +
+				"(hoff) ",
+		},
+		{
+			name:       "Can debug a workflow with forced decision, breakpoint, and context edition",
+			givenArgs:  []string{"debug", "testdata/has_key.json"},
+			givenStdin: "set key \"value\"\nforce hasKey false\nbreak missing\nc\nunset key\nc\n",
+			expectedStdout: "(hoff) + key = \"value\"\n" +
+				"(hoff) hasKey forced on false\n" +
+				"(hoff) breakpoint on missing\n" +
+				"(hoff) paused before missing\n" +
+				"(hoff) - key\n" +
+				"(hoff) aborted: missing key\n" +
+				"(hoff) \n",
+		},
+		{
+			name:       "Can debug a workflow with a watched key",
+			givenArgs:  []string{"debug", "-input", "testdata/has_key_input.json", "testdata/has_key.json"},
+			givenStdin: "watch found\nc\nc\nbreak unknown\nforce found true\nunknown\nq\n",
+			expectedStdout: "(hoff) watching found\n" +
+				"(hoff) found: 'Continue'\n" +
+				"+ found = true\n" +
+				"paused: found written\n" +
+				"(hoff) finished\n" +
+				"(hoff) unknown node 'unknown'\n" +
+				"(hoff) can't force the decision of a node without decide capability: found\n" +
+				"(hoff) unknown command 'unknown' (type help for the commands)\n" +
+				"(hoff) ",
+		},
+		{
+			name:           "Can't run an unknown command",
+			givenArgs:      []string{"unknown"},
+			expectedCode:   2,
+			expectedStderr: "hoff: unknown command 'unknown'\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(testCase.givenArgs, strings.NewReader(testCase.givenStdin), &stdout, &stderr)
+
+			if code != testCase.expectedCode {
+				t.Errorf("code - got: %+v, want: %+v", code, testCase.expectedCode)
+			}
+			if diff := cmp.Diff(stdout.String(), testCase.expectedStdout); diff != "" {
+				t.Errorf("stdout - (-got +want):\n%v", diff)
+			}
+			if testCase.expectedStderr != "" && !bytes.HasPrefix(stderr.Bytes(), []byte(testCase.expectedStderr)) {
+				t.Errorf("stderr - got: %v, want: %v", stderr.String(), testCase.expectedStderr)
+			}
+		})
+	}
+}
+
+func Test_debugSession_with_nested_write(t *testing.T) {
+	addItem, _ := hoff.NewActionNode("addItem", func(c *hoff.Context) error {
+		order, _ := c.Read("order")
+		order.(map[string]interface{})["item"] = "book"
+		return nil
+	})
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(addItem)
+	ns.Activate()
+	cp, _ := hoff.NewComputation(ns, hoff.NewContext(map[string]interface{}{"order": map[string]interface{}{}}))
+
+	var stdout bytes.Buffer
+	session := &debugSession{
+		workflow:    &loadedWorkflow{system: ns, nodes: map[string]hoff.Node{"addItem": addItem}},
+		computation: cp,
+		out:         &stdout,
+	}
+	session.execute([]string{"watch", "order"})
+	session.execute([]string{"c"})
+
+	expectedStdout := "watching order\n" +
+		"addItem: 'Continue'\n" +
+		"+ order = {\"item\":\"book\"}\n" +
+		"paused: order written\n"
+	if diff := cmp.Diff(stdout.String(), expectedStdout); diff != "" {
+		t.Errorf("stdout - (-got +want):\n%v", diff)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"reflect"
+
+	"github.com/rlespinasse/hoff"
+)
+
+type actionFactory func(args map[string]interface{}) (func(*hoff.Context) error, error)
+
+type decisionFactory func(args map[string]interface{}) (func(*hoff.Context) (bool, error), error)
+
+// registry hold the functions usable by the nodes of a workflow.
+type registry struct {
+	actions   map[string]actionFactory
+	decisions map[string]decisionFactory
+}
+
+// newBuiltinRegistry create a registry with the built-in functions.
+func newBuiltinRegistry() *registry {
+	return &registry{
+		actions: map[string]actionFactory{
+			"noop": func(args map[string]interface{}) (func(*hoff.Context) error, error) {
+				return func(*hoff.Context) error { return nil }, nil
+			},
+			"store": func(args map[string]interface{}) (func(*hoff.Context) error, error) {
+				key, err := stringArg(args, "key")
+				if err != nil {
+					return nil, err
+				}
+				value := args["value"]
+				return func(c *hoff.Context) error {
+					c.Store(key, value)
+					return nil
+				}, nil
+			},
+			"copy": func(args map[string]interface{}) (func(*hoff.Context) error, error) {
+				from, err := stringArg(args, "from")
+				if err != nil {
+					return nil, err
+				}
+				to, err := stringArg(args, "to")
+				if err != nil {
+					return nil, err
+				}
+				return func(c *hoff.Context) error {
+					value, found := c.Read(from)
+					if !found {
+						return fmt.Errorf("missing '%v' in context", from)
+					}
+					c.Store(to, value)
+					return nil
+				}, nil
+			},
+			"delete": func(args map[string]interface{}) (func(*hoff.Context) error, error) {
+				key, err := stringArg(args, "key")
+				if err != nil {
+					return nil, err
+				}
+				return func(c *hoff.Context) error {
+					c.Delete(key)
+					return nil
+				}, nil
+			},
+			"fail": func(args map[string]interface{}) (func(*hoff.Context) error, error) {
+				message, err := stringArg(args, "message")
+				if err != nil {
+					return nil, err
+				}
+				return func(*hoff.Context) error { return errors.New(message) }, nil
+			},
+		},
+		decisions: map[string]decisionFactory{
+			"has_key": func(args map[string]interface{}) (func(*hoff.Context) (bool, error), error) {
+				key, err := stringArg(args, "key")
+				if err != nil {
+					return nil, err
+				}
+				return func(c *hoff.Context) (bool, error) {
+					return c.HaveKey(key), nil
+				}, nil
+			},
+			"equals": func(args map[string]interface{}) (func(*hoff.Context) (bool, error), error) {
+				key, err := stringArg(args, "key")
+				if err != nil {
+					return nil, err
+				}
+				expected := args["value"]
+				return func(c *hoff.Context) (bool, error) {
+					value, _ := c.Read(key)
+					return reflect.DeepEqual(value, expected), nil
+				}, nil
+			},
+		},
+	}
+}
+
+// loadPlugin add the functions exported by a go plugin as 'Actions' (map[string]func(*hoff.Context) error),
+// and 'Decisions' (map[string]func(*hoff.Context) (bool, error)) variables.
+func (r *registry) loadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	if symbol, err := p.Lookup("Actions"); err == nil {
+		actions, ok := symbol.(*map[string]func(*hoff.Context) error)
+		if !ok {
+			return fmt.Errorf("%v: 'Actions' must be a map[string]func(*hoff.Context) error", path)
+		}
+		for name, actionFunc := range *actions {
+			fn := actionFunc
+			r.actions[name] = func(map[string]interface{}) (func(*hoff.Context) error, error) { return fn, nil }
+		}
+		found = true
+	}
+	if symbol, err := p.Lookup("Decisions"); err == nil {
+		decisions, ok := symbol.(*map[string]func(*hoff.Context) (bool, error))
+		if !ok {
+			return fmt.Errorf("%v: 'Decisions' must be a map[string]func(*hoff.Context) (bool, error)", path)
+		}
+		for name, decisionFunc := range *decisions {
+			fn := decisionFunc
+			r.decisions[name] = func(map[string]interface{}) (func(*hoff.Context) (bool, error), error) { return fn, nil }
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("%v: plugin must export 'Actions', or 'Decisions'", path)
+	}
+	return nil
+}
+
+// newNode create the node of a workflow node definition.
+func (r *registry) newNode(definition workflowNode) (hoff.Node, error) {
+	if definition.Name == "" {
+		return nil, errors.New("can't have node without name")
+	}
+	switch definition.Type {
+	case "action":
+		factory, found := r.actions[definition.Func]
+		if !found {
+			return nil, fmt.Errorf("can't have unknown action function '%v'", definition.Func)
+		}
+		actionFunc, err := factory(definition.Args)
+		if err != nil {
+			return nil, err
+		}
+		return hoff.NewActionNode(definition.Name, actionFunc)
+	case "decision":
+		factory, found := r.decisions[definition.Func]
+		if !found {
+			return nil, fmt.Errorf("can't have unknown decision function '%v'", definition.Func)
+		}
+		decisionFunc, err := factory(definition.Args)
+		if err != nil {
+			return nil, err
+		}
+		return hoff.NewDecisionNode(definition.Name, decisionFunc)
+	}
+	return nil, fmt.Errorf("can't have unknown node type '%v'", definition.Type)
+}
+
+func stringArg(args map[string]interface{}, name string) (string, error) {
+	value, found := args[name]
+	if !found {
+		return "", fmt.Errorf("can't have missing '%v' argument", name)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("can't have non-string '%v' argument", name)
+	}
+	return s, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/rlespinasse/hoff"
+)
+
+// workflow is the declarative definition of a node system.
+type workflow struct {
+	Nodes []workflowNode `json:"nodes"`
+	Links []workflowLink `json:"links"`
+}
+
+// workflowNode define a node by its name, its type (action or decision),
+// the built-in (or plugin) function to use, and its join mode.
+type workflowNode struct {
+	Name string                 `json:"name"`
+	Type string                 `json:"type"`
+	Func string                 `json:"func"`
+	Args map[string]interface{} `json:"args"`
+	Join string                 `json:"join"`
+}
+
+// workflowLink define a link between two nodes by their names (used as node identifiers).
+type workflowLink struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Branch *bool  `json:"branch"`
+}
+
+// loadedWorkflow hold a node system created from a workflow,
+// and the location of each node and link in the workflow file.
+type loadedWorkflow struct {
+	path          string
+	system        *hoff.NodeSystem
+	nodes         map[string]hoff.Node
+	nodeLocations map[hoff.Node]string
+	linkLocations map[string]string
+}
+
+// loadWorkflow read a workflow file and create its (unactivated) node system.
+func loadWorkflow(path string, reg *registry) (*loadedWorkflow, []error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+	var w workflow
+	if err := json.Unmarshal(content, &w); err != nil {
+		return nil, []error{fmt.Errorf("%v: %v", path, err)}
+	}
+	return newLoadedWorkflow(path, w, reg)
+}
+
+func newLoadedWorkflow(path string, w workflow, reg *registry) (*loadedWorkflow, []error) {
+	lw := &loadedWorkflow{
+		path:          path,
+		system:        hoff.NewNodeSystem(),
+		nodes:         make(map[string]hoff.Node),
+		nodeLocations: make(map[hoff.Node]string),
+		linkLocations: make(map[string]string),
+	}
+
+	errs := make([]error, 0)
+	for i, definition := range w.Nodes {
+		location := fmt.Sprintf("nodes[%v]", i)
+		if _, found := lw.nodes[definition.Name]; found {
+			errs = append(errs, lw.errorAt(location, fmt.Errorf("can't have multiple nodes named '%v'", definition.Name)))
+			continue
+		}
+		node, err := reg.newNode(definition)
+		if err != nil {
+			errs = append(errs, lw.errorAt(location, err))
+			continue
+		}
+		lw.nodes[definition.Name] = node
+		lw.nodeLocations[node] = location
+		lw.system.AddNode(node)
+		if definition.Join != "" {
+			mode, err := parseJoinMode(definition.Join)
+			if err != nil {
+				errs = append(errs, lw.errorAt(location, err))
+				continue
+			}
+			lw.system.ConfigureJoinModeOnNode(node, mode)
+		}
+	}
+
+	for i, definition := range w.Links {
+		location := fmt.Sprintf("links[%v]", i)
+		from, foundFrom := lw.nodes[definition.From]
+		if !foundFrom {
+			errs = append(errs, lw.errorAt(location, fmt.Errorf("can't have unknown node '%v' as 'from'", definition.From)))
+			continue
+		}
+		to, foundTo := lw.nodes[definition.To]
+		if !foundTo {
+			errs = append(errs, lw.errorAt(location, fmt.Errorf("can't have unknown node '%v' as 'to'", definition.To)))
+			continue
+		}
+		var err error
+		if definition.Branch == nil {
+			_, err = lw.system.AddLink(from, to)
+		} else {
+			_, err = lw.system.AddLinkOnBranch(from, to, *definition.Branch)
+		}
+		if err != nil {
+			errs = append(errs, lw.errorAt(location, err))
+			continue
+		}
+		key := linkKey(hoff.NodeLink{From: from, To: to, Branch: definition.Branch})
+		if _, found := lw.linkLocations[key]; !found {
+			lw.linkLocations[key] = location
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return lw, nil
+}
+
+// validate check the node system and give its errors with their locations in the workflow file.
+func (lw *loadedWorkflow) validate() []error {
+	_, errs := lw.system.IsValid()
+	located := make([]error, 0, len(errs))
+	for _, err := range errs {
+		located = append(located, lw.errorAt(lw.locate(err), err))
+	}
+	return located
+}
+
+// locate find the location in the workflow file of the nodes, or links, of a validation error.
+func (lw *loadedWorkflow) locate(err error) string {
+	var decisionErr hoff.DecisionNodeWithoutLinkError
+	var cycleErr hoff.CycleError
+	var undeclaredErr hoff.UndeclaredNodeError
+	var instancesErr hoff.MultipleInstancesError
+	var joinModeErr hoff.MissingJoinModeError
+	switch {
+	case errors.As(err, &decisionErr):
+		return lw.nodeLocations[decisionErr.Node]
+	case errors.As(err, &cycleErr):
+		locations := make([]string, 0, len(cycleErr.Links))
+		for _, link := range cycleErr.Links {
+			locations = append(locations, lw.linkLocations[linkKey(link)])
+		}
+		return strings.Join(locations, ",")
+	case errors.As(err, &undeclaredErr):
+		return lw.linkLocations[linkKey(undeclaredErr.Link)]
+	case errors.As(err, &instancesErr):
+		return lw.nodeLocations[instancesErr.Node]
+	case errors.As(err, &joinModeErr):
+		return lw.nodeLocations[joinModeErr.Node]
+	}
+	return ""
+}
+
+func (lw *loadedWorkflow) errorAt(location string, err error) error {
+	if location == "" {
+		return fmt.Errorf("%v: %v", lw.path, err)
+	}
+	return fmt.Errorf("%v: %v: %v", lw.path, location, err)
+}
+
+func parseJoinMode(value string) (hoff.JoinMode, error) {
+	switch hoff.JoinMode(value) {
+	case hoff.JoinAnd:
+		return hoff.JoinAnd, nil
+	case hoff.JoinOr:
+		return hoff.JoinOr, nil
+	case hoff.JoinNone:
+		return hoff.JoinNone, nil
+	}
+	return hoff.JoinNone, fmt.Errorf("can't have unknown join mode '%v'", value)
+}
+
+func linkKey(link hoff.NodeLink) string {
+	branch := ""
+	if link.Branch != nil {
+		branch = fmt.Sprintf("%v", *link.Branch)
+	}
+	return fmt.Sprintf("%v>%v>%v", link.From.ID(), link.To.ID(), branch)
+}
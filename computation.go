@@ -1,6 +1,7 @@
 package hoff
 
 import (
+	"context"
 	"errors"
 
 	"github.com/google/go-cmp/cmp"
@@ -12,6 +13,19 @@ type Computation struct {
 	Context *Context
 	Status  bool
 	Report  map[Node]ComputeState
+	Path    []Node
+	Trace   []NodeTrace
+
+	ctx      context.Context
+	simulate DecisionStrategy
+	started  bool
+	finished bool
+	pending  []Node
+
+	breakpoints     map[Node]bool
+	keyBreakpoints  map[string]bool
+	pausedAt        Node
+	forcedDecisions map[Node]bool
 }
 
 // NewComputation create a computation based on a valid, and activated NodeSystem and a Context.
@@ -39,55 +53,136 @@ func (cp Computation) Equal(o Computation) bool {
 
 // Compute run all nodes in the defined order to enhance the Context.
 // At the end of the computation (Status at true), you can read the compute state
-// of each node in the Report.
+// of each node in the Report, the computed nodes in order in the Path,
+// and the durations waited by the nodes with limits in the Trace.
 func (cp *Computation) Compute() error {
-	cp.Report = make(map[Node]ComputeState)
-	err := cp.computeNodes(cp.System.InitialNodes())
-	if err != nil {
-		return err
-	}
-	cp.Status = true
-	return nil
+	return cp.ComputeContext(context.Background())
 }
 
-func (cp *Computation) computeNodes(nodes []Node) error {
-	for _, node := range nodes {
-		err := cp.computeNode(node)
-		if err != nil {
+// ComputeContext run all nodes like Compute, but stop before computing a node
+// when the context is done (cancelled, or timed out) and return the context error.
+func (cp *Computation) ComputeContext(ctx context.Context) error {
+	cp.start(ctx)
+	for !cp.finished {
+		if _, err := cp.step(false); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (cp *Computation) computeNode(node Node) error {
-	order := cp.calculateComputeOrder(node)
+// DryRun walk all nodes like Compute without running them: the action nodes continue without side effect,
+// and the decision nodes take the branch given by a decision strategy.
+// At the end, you can read the compute state of each node in the Report, and the nodes who would be computed in the Path.
+func (cp *Computation) DryRun(strategy DecisionStrategy) error {
+	if strategy == nil {
+		return errors.New("must have a decision strategy to dry run")
+	}
+	cp.simulate = strategy
+	defer func() {
+		cp.simulate = nil
+	}()
+	return cp.ComputeContext(context.Background())
+}
 
+// start prepare the computation to walk the nodes from the initial nodes.
+func (cp *Computation) start(ctx context.Context) {
+	cp.ctx = ctx
+	cp.Status = false
+	cp.Report = make(map[Node]ComputeState)
+	cp.Path = make([]Node, 0)
+	cp.Trace = nil
+	cp.pending = pushNodes(make([]Node, 0), cp.System.InitialNodes())
+	cp.started = true
+	cp.finished = false
+}
+
+// next drop the pending nodes who don't need to be reported (not ready, or already reported),
+// and give the next node to report with its compute order.
+func (cp *Computation) next() (Node, computeOrder, bool) {
+	for len(cp.pending) > 0 {
+		node := cp.pending[len(cp.pending)-1]
+		order := cp.calculateComputeOrder(node)
+		if order == computeIt || order == skipIt {
+			return node, order, true
+		}
+		cp.pending = cp.pending[:len(cp.pending)-1]
+	}
+	return nil, "", false
+}
+
+// step report the next node (skipped, or computed), and add its following nodes to the pending nodes.
+// The nodes are walked in depth-first order, the following nodes of a node being walked before its next siblings.
+func (cp *Computation) step(recordWrites bool) (Step, error) {
+	node, order, found := cp.next()
+	if !found {
+		cp.finished = true
+		cp.Status = true
+		return Step{}, nil
+	}
+
+	step := Step{Node: node}
 	switch order {
-	case dontRunIt, alreadyRunOnce:
-		return nil
 	case skipIt:
-		cp.Report[node] = NewSkipComputeState()
+		step.State = NewSkipComputeState()
 	case computeIt:
-		state := node.Compute(cp.Context)
-		cp.Report[node] = state
-		if state.Value == AbortState {
-			return state.Error
+		if err := cp.ctx.Err(); err != nil {
+			return Step{}, err
+		}
+		var before map[string]interface{}
+		if recordWrites {
+			before = deepCopyData(cp.Context.Data)
 		}
+		if branch, forced := cp.forcedDecisions[node]; forced && node.DecideCapability() {
+			step.State = NewContinueOnBranchComputeState(branch)
+		} else if cp.simulate != nil {
+			step.State = simulateNode(node, cp.Context, cp.simulate)
+		} else {
+			step.State = cp.computeOrCache(node)
+		}
+		if recordWrites {
+			step.Writes = newContextWrites(before, cp.Context)
+		}
+		cp.Path = append(cp.Path, node)
+	}
+	cp.Report[node] = step.State
+	cp.pending = cp.pending[:len(cp.pending)-1]
+	if step.State.Value == AbortState || step.State.Value == RejectState {
+		cp.finished = true
+		return step, step.State.Error
 	}
 
-	return cp.computeFollowingNodes(node, nodeBranches(node)...)
+	following := make([]Node, 0)
+	for _, branch := range nodeBranches(node) {
+		nextNodes, _ := cp.System.Follow(node, branch)
+		following = append(following, nextNodes...)
+	}
+	cp.pending = pushNodes(cp.pending, following)
+	return step, nil
 }
 
-func (cp *Computation) computeFollowingNodes(node Node, branches ...*bool) error {
-	for _, branch := range branches {
-		nextNodes, _ := cp.System.Follow(node, branch)
-		err := cp.computeNodes(nextNodes)
-		if err != nil {
-			return err
-		}
+// computeOrCache compute a node, or apply the cached context writes of a cacheable node.
+func (cp *Computation) computeOrCache(node Node) ComputeState {
+	cacheable, ok := node.(CacheableNode)
+	if !ok {
+		return cp.guardedCompute(node)
 	}
-	return nil
+	key, ok := cacheable.CacheKey(cp.Context)
+	if !ok {
+		return cp.guardedCompute(node)
+	}
+	if writes, found := cacheable.Cache().Get(key); found {
+		writes.apply(cp.Context)
+		return NewCachedComputeState()
+	}
+
+	// a deep copy to find the nested values written in place by the node
+	before := deepCopyData(cp.Context.Data)
+	state := cp.guardedCompute(node)
+	if state.Value == ContinueState {
+		cacheable.Cache().Set(key, newContextWrites(before, cp.Context))
+	}
+	return state
 }
 
 func (cp *Computation) calculateComputeOrder(node Node) computeOrder {
@@ -161,6 +256,14 @@ const (
 	alreadyRunOnce              = "already_run_once"
 )
 
+// pushNodes add nodes on a stack of nodes, the first node being on top.
+func pushNodes(stack []Node, nodes []Node) []Node {
+	for i := len(nodes) - 1; i >= 0; i-- {
+		stack = append(stack, nodes[i])
+	}
+	return stack
+}
+
 func nodeBranches(node Node) []*bool {
 	if node.DecideCapability() {
 		return []*bool{boolPointer(true), boolPointer(false)}
@@ -6,4 +6,6 @@ type ComputationMode string
 const (
 	// SequentialComputation will run sequential computations in the engine.
 	SequentialComputation ComputationMode = "seq"
+	// ParallelComputation will run concurrent computations in the engine.
+	ParallelComputation = "parallel"
 )
@@ -1,6 +1,7 @@
 package hoff
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -99,7 +100,7 @@ func Test_Computation_Compute(t *testing.T) {
 		name                string
 		givenNodes          []Node
 		givenNodesJoinModes map[Node]JoinMode
-		givenLinks          []nodeLink
+		givenLinks          []NodeLink
 		givenContextData    map[string]interface{}
 		expectedStatus      bool
 		expectedContextData map[string]interface{}
@@ -146,7 +147,7 @@ func Test_Computation_Compute(t *testing.T) {
 				writeAction,
 				readAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, readAction),
 			},
 			expectedStatus: true,
@@ -165,7 +166,7 @@ func Test_Computation_Compute(t *testing.T) {
 				readAction,
 				writeAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, readAction),
 			},
 			expectedStatus: true,
@@ -186,7 +187,7 @@ func Test_Computation_Compute(t *testing.T) {
 				readAction,
 				deleteAnotherAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, writeActionKeyIsPresent),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, readAction, true),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, deleteAnotherAction, false),
@@ -211,7 +212,7 @@ func Test_Computation_Compute(t *testing.T) {
 				readAction,
 				deleteAnotherAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAnotherAction, writeActionKeyIsPresent),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, readAction, true),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, deleteAnotherAction, false),
@@ -233,7 +234,7 @@ func Test_Computation_Compute(t *testing.T) {
 				writeActionKeyIsPresent,
 				writeAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, writeActionKeyIsPresent),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, readAction, true),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, deleteAnotherAction, false),
@@ -258,7 +259,7 @@ func Test_Computation_Compute(t *testing.T) {
 				writeActionKeyIsPresent,
 				writeAnotherAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAnotherAction, writeActionKeyIsPresent),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, readAction, true),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, deleteAnotherAction, false),
@@ -281,7 +282,7 @@ func Test_Computation_Compute(t *testing.T) {
 				readAction,
 				deleteAnotherAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, writeActionKeyIsPresent),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, errorAction, true),
 				newNodeLink(errorAction, readAction),
@@ -305,7 +306,7 @@ func Test_Computation_Compute(t *testing.T) {
 				errorAction,
 				readAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAnotherAction, writeActionKeyIsPresent),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, errorAction, false),
 				newNodeLink(errorAction, readAction),
@@ -328,7 +329,7 @@ func Test_Computation_Compute(t *testing.T) {
 				readAction,
 				deleteAnotherAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, errorDecision),
 				newNodeLinkOnBranch(errorDecision, readAction, true),
 				newNodeLinkOnBranch(errorDecision, deleteAnotherAction, false),
@@ -350,7 +351,7 @@ func Test_Computation_Compute(t *testing.T) {
 				errorAction,
 				deleteAnotherAction,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, deleteAnotherAction),
 				newNodeLink(writeAction, errorAction),
 				newNodeLink(writeAction, readAction),
@@ -376,7 +377,7 @@ func Test_Computation_Compute(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				readAction: JoinAnd,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, readAction),
 				newNodeLink(writeAnotherAction, readAction),
 				newNodeLink(readAction, deleteAnotherAction),
@@ -405,7 +406,7 @@ func Test_Computation_Compute(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				readAction: JoinAnd,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, readAction),
 				newNodeLinkOnBranch(writeActionKeyIsPresent, readAction, false),
 				newNodeLink(writeAnotherAction, readAction),
@@ -435,7 +436,7 @@ func Test_Computation_Compute(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				readAction: JoinOr,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, readAction),
 				newNodeLinkOnBranch(writeAnotherActionKeyIsPresent, readAction, true),
 				newNodeLink(readAction, deleteAnotherAction),
@@ -463,7 +464,7 @@ func Test_Computation_Compute(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				readAction: JoinOr,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(writeActionKeyIsPresent, readAction, true),
 				newNodeLinkOnBranch(writeAnotherActionKeyIsPresent, readAction, true),
 				newNodeLink(readAction, deleteAnotherAction),
@@ -488,7 +489,7 @@ func Test_Computation_Compute(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				readAction: JoinOr,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(writeAction, readAction),
 				newNodeLink(errorAction, readAction),
 				newNodeLink(readAction, deleteAnotherAction),
@@ -612,3 +613,24 @@ func testGithubIssue11JoinMode(mode JoinMode, t *testing.T) {
 		t.Errorf("run order - got: %+v, want: %+v", resultData, expectedData)
 	}
 }
+
+func Test_Computation_ComputeContext_cancelled(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.Activate()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cp, _ := NewComputation(ns, NewContextWithoutData())
+	err := cp.ComputeContext(ctx)
+
+	if err != context.Canceled {
+		t.Errorf("error - got: %+v, want: %+v", err, context.Canceled)
+	}
+	if cp.Status || len(cp.Report) != 0 {
+		t.Errorf("computation must not have computed any node, got: %+v", cp.Report)
+	}
+}
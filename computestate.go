@@ -9,6 +9,7 @@ type ComputeState struct {
 	Value  StateType
 	Branch *bool
 	Error  error
+	Cached bool
 }
 
 // String print human-readable version of a compute state
@@ -21,7 +22,11 @@ func (cs ComputeState) String() string {
 	if cs.Error != nil {
 		err = fmt.Sprintf(" on %v", cs.Error)
 	}
-	return fmt.Sprintf("'%v%v%v'", cs.Value, branch, err)
+	cached := ""
+	if cs.Cached {
+		cached = " from cache"
+	}
+	return fmt.Sprintf("'%v%v%v%v'", cs.Value, branch, err, cached)
 }
 
 // NewContinueComputeState generate a computation state to continue to following nodes
@@ -40,6 +45,15 @@ func NewContinueOnBranchComputeState(branch bool) ComputeState {
 	}
 }
 
+// NewCachedComputeState generate a computation state to continue to following nodes
+// without computing the Node, its context writes being cached
+func NewCachedComputeState() ComputeState {
+	return ComputeState{
+		Value:  ContinueState,
+		Cached: true,
+	}
+}
+
 // NewSkipComputeState generate a computation state to specify
 // that the Node computation have been skipped
 func NewSkipComputeState() ComputeState {
@@ -48,6 +62,14 @@ func NewSkipComputeState() ComputeState {
 	}
 }
 
+// NewRejectComputeState generate a computation state to reject the Node computation due to a limit
+func NewRejectComputeState(err error) ComputeState {
+	return ComputeState{
+		Value: RejectState,
+		Error: err,
+	}
+}
+
 // NewAbortComputeState generate a computation state to throw an unexpected error
 func NewAbortComputeState(err error) ComputeState {
 	return ComputeState{
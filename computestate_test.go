@@ -29,6 +29,12 @@ func Test_ComputeState_Call(t *testing.T) {
 			expectedNodeBranch:    boolPointer(true),
 			expectedString:        "'Continue on true'",
 		},
+		{
+			name:                  "Should generate a cached continue state",
+			givenComputeStateCall: func() ComputeState { return NewCachedComputeState() },
+			expectedState:         ContinueState,
+			expectedString:        "'Continue from cache'",
+		},
 		{
 			name:                  "Should generate a skip state",
 			givenComputeStateCall: func() ComputeState { return NewSkipComputeState() },
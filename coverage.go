@@ -0,0 +1,216 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+)
+
+// Coverage collect, across many computations on a node system, the computed nodes,
+// the branches taken by the decision nodes, and the outcomes (joined, or skipped) of the nodes with a join mode.
+// A node is covered when it continue, an aborted, or rejected, node being not covered.
+type Coverage struct {
+	mutex        sync.Mutex
+	system       *NodeSystem
+	computations int
+	nodes        map[Node]int
+	branches     map[Node]map[bool]int
+	joins        map[Node]map[bool]int
+}
+
+// NewCoverage create an empty coverage of a node system.
+func NewCoverage(system *NodeSystem) (*Coverage, error) {
+	if system == nil {
+		return nil, errors.New("must have a node system to collect coverage")
+	}
+	return &Coverage{
+		system:   system,
+		nodes:    make(map[Node]int),
+		branches: make(map[Node]map[bool]int),
+		joins:    make(map[Node]map[bool]int),
+	}, nil
+}
+
+// ConfigureCoverage record each computation of the engine in a coverage,
+// except the ones on another node system than the coverage one (like a variant, or a swapped node system).
+func (e *Engine) ConfigureCoverage(coverage *Coverage) {
+	e.systemMutex.Lock()
+	defer e.systemMutex.Unlock()
+	e.coverage = coverage
+}
+
+// Record add the report of a computation result to the coverage.
+func (c *Coverage) Record(result ComputationResult) {
+	c.record(result.Report)
+}
+
+// RecordComputation add the report of a computation to the coverage.
+func (c *Coverage) RecordComputation(cp *Computation) {
+	c.record(cp.Report)
+}
+
+func (c *Coverage) record(report map[Node]ComputeState) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.computations++
+	for _, node := range c.system.nodes {
+		state, found := report[node]
+		if !found {
+			continue
+		}
+		if state.Value == ContinueState {
+			c.nodes[node]++
+			if state.Branch != nil {
+				countOutcome(c.branches, node, *state.Branch)
+			}
+		}
+		if c.system.JoinModeOfNode(node) != JoinNone {
+			countOutcome(c.joins, node, state.Value != SkipState)
+		}
+	}
+}
+
+// CoverageBranch is a branch of a decision node.
+type CoverageBranch struct {
+	Node   string
+	Branch bool
+}
+
+// CoverageJoin is an outcome of a node with a join mode (joined, or skipped).
+type CoverageJoin struct {
+	Node   string
+	Joined bool
+}
+
+// CoverageReport give the count of the covered nodes, branches, and join outcomes,
+// and the ones who never happened.
+type CoverageReport struct {
+	Computations      int
+	Nodes             int
+	CoveredNodes      int
+	Branches          int
+	CoveredBranches   int
+	Joins             int
+	CoveredJoins      int
+	UncoveredNodes    []string
+	UncoveredBranches []CoverageBranch
+	UncoveredJoins    []CoverageJoin
+}
+
+// Report give the coverage of the recorded computations.
+func (c *Coverage) Report() CoverageReport {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	report := CoverageReport{
+		Computations: c.computations,
+	}
+	for _, node := range c.system.nodes {
+		report.Nodes++
+		if c.nodes[node] > 0 {
+			report.CoveredNodes++
+		} else {
+			report.UncoveredNodes = append(report.UncoveredNodes, node.ID())
+		}
+		if node.DecideCapability() {
+			for _, branch := range []bool{true, false} {
+				report.Branches++
+				if c.branches[node][branch] > 0 {
+					report.CoveredBranches++
+				} else {
+					report.UncoveredBranches = append(report.UncoveredBranches, CoverageBranch{Node: node.ID(), Branch: branch})
+				}
+			}
+		}
+		if c.system.JoinModeOfNode(node) != JoinNone {
+			for _, joined := range []bool{true, false} {
+				report.Joins++
+				if c.joins[node][joined] > 0 {
+					report.CoveredJoins++
+				} else {
+					report.UncoveredJoins = append(report.UncoveredJoins, CoverageJoin{Node: node.ID(), Joined: joined})
+				}
+			}
+		}
+	}
+	return report
+}
+
+// IsComplete tell if all nodes, branches, and join outcomes are covered.
+func (r CoverageReport) IsComplete() bool {
+	return r.CoveredNodes == r.Nodes && r.CoveredBranches == r.Branches && r.CoveredJoins == r.Joins
+}
+
+// String render the coverage as text, with the nodes, branches, and join outcomes who never happened.
+func (r CoverageReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "computations: %v\n", r.Computations)
+	fmt.Fprintf(&b, "nodes: %v/%v covered\n", r.CoveredNodes, r.Nodes)
+	fmt.Fprintf(&b, "branches: %v/%v covered\n", r.CoveredBranches, r.Branches)
+	fmt.Fprintf(&b, "joins: %v/%v covered\n", r.CoveredJoins, r.Joins)
+	for _, id := range r.UncoveredNodes {
+		fmt.Fprintf(&b, "- node never computed: %v\n", id)
+	}
+	for _, branch := range r.UncoveredBranches {
+		fmt.Fprintf(&b, "- branch never taken: %v on %v\n", branch.Node, branch.Branch)
+	}
+	for _, join := range r.UncoveredJoins {
+		fmt.Fprintf(&b, "- join never %v: %v\n", joinOutcome(join.Joined), join.Node)
+	}
+	return b.String()
+}
+
+// HTML render the coverage as a HTML page with the mermaid graph of the node system,
+// whose never computed nodes, and never taken branches, are highlighted.
+func (c *Coverage) HTML() string {
+	report := c.Report()
+	uncoveredNodes := make(map[string]bool)
+	for _, id := range report.UncoveredNodes {
+		uncoveredNodes[id] = true
+	}
+	uncoveredBranches := make(map[CoverageBranch]bool)
+	for _, branch := range report.UncoveredBranches {
+		uncoveredBranches[branch] = true
+	}
+
+	var graph strings.Builder
+	graph.WriteString(c.system.Mermaid())
+	graph.WriteString("\tclassDef covered fill:#c8f7c5,stroke:#2e7d32\n")
+	graph.WriteString("\tclassDef uncovered fill:#f7c5c5,stroke:#c62828\n")
+	for i, node := range c.system.nodes {
+		class := "covered"
+		if uncoveredNodes[node.ID()] {
+			class = "uncovered"
+		}
+		fmt.Fprintf(&graph, "\tclass n%v %v\n", i, class)
+	}
+	for i, link := range c.system.links {
+		if link.Branch != nil && uncoveredBranches[CoverageBranch{Node: link.From.ID(), Branch: *link.Branch}] {
+			fmt.Fprintf(&graph, "\tlinkStyle %v stroke:#c62828,stroke-dasharray:5\n", i)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>hoff coverage</title>\n")
+	b.WriteString("<script src=\"https://cdn.jsdelivr.net/npm/mermaid/dist/mermaid.min.js\"></script>\n")
+	b.WriteString("<script>mermaid.initialize({startOnLoad: true});</script>\n</head>\n<body>\n")
+	fmt.Fprintf(&b, "<pre>%v</pre>\n", html.EscapeString(report.String()))
+	fmt.Fprintf(&b, "<pre class=\"mermaid\">\n%v</pre>\n", html.EscapeString(graph.String()))
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+func countOutcome(outcomes map[Node]map[bool]int, node Node, outcome bool) {
+	if outcomes[node] == nil {
+		outcomes[node] = make(map[bool]int)
+	}
+	outcomes[node][outcome]++
+}
+
+func joinOutcome(joined bool) string {
+	if joined {
+		return "joined"
+	}
+	return "skipped"
+}
@@ -0,0 +1,184 @@
+package hoff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Coverage(t *testing.T) {
+	keyIsPresent, _ := NewDecisionNode("keyIsPresent", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	storeFound, _ := NewActionNode("storeFound", func(c *Context) error {
+		c.Store("found", true)
+		return nil
+	})
+	storeMissing, _ := NewActionNode("storeMissing", func(c *Context) error {
+		c.Store("found", false)
+		return nil
+	})
+	done, _ := NewActionNode("done", func(c *Context) error { return nil })
+	after, _ := NewActionNode("after", func(c *Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(storeFound)
+	ns.AddNode(storeMissing)
+	ns.AddNode(done)
+	ns.AddNode(after)
+	ns.AddLinkOnBranch(keyIsPresent, storeFound, true)
+	ns.AddLinkOnBranch(keyIsPresent, storeMissing, false)
+	ns.AddLink(storeFound, done)
+	ns.AddLink(storeMissing, done)
+	ns.AddLink(done, after)
+	ns.ConfigureJoinModeOnNode(done, JoinOr)
+	ns.ConfigureJoinModeOnNode(after, JoinAnd)
+	ns.Activate()
+
+	coverage, _ := NewCoverage(ns)
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureCoverage(coverage)
+
+	testCases := []struct {
+		name           string
+		givenData      []map[string]interface{}
+		expectedReport CoverageReport
+		expectedText   string
+	}{
+		{
+			name:      "Coverage of a computation",
+			givenData: []map[string]interface{}{{"key": "value"}},
+			expectedReport: CoverageReport{
+				Computations:      1,
+				Nodes:             5,
+				CoveredNodes:      4,
+				Branches:          2,
+				CoveredBranches:   1,
+				Joins:             4,
+				CoveredJoins:      2,
+				UncoveredNodes:    []string{"storeMissing"},
+				UncoveredBranches: []CoverageBranch{{Node: "keyIsPresent", Branch: false}},
+				UncoveredJoins:    []CoverageJoin{{Node: "done", Joined: false}, {Node: "after", Joined: false}},
+			},
+			expectedText: "computations: 1\n" +
+				"nodes: 4/5 covered\n" +
+				"branches: 1/2 covered\n" +
+				"joins: 2/4 covered\n" +
+				"- node never computed: storeMissing\n" +
+				"- branch never taken: keyIsPresent on false\n" +
+				"- join never skipped: done\n" +
+				"- join never skipped: after\n",
+		},
+		{
+			name:      "Coverage of many computations",
+			givenData: []map[string]interface{}{{}},
+			expectedReport: CoverageReport{
+				Computations:    2,
+				Nodes:           5,
+				CoveredNodes:    5,
+				Branches:        2,
+				CoveredBranches: 2,
+				Joins:           4,
+				CoveredJoins:    2,
+				UncoveredJoins:  []CoverageJoin{{Node: "done", Joined: false}, {Node: "after", Joined: false}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for _, data := range testCase.givenData {
+				eng.Compute(data)
+			}
+			report := coverage.Report()
+
+			if !cmp.Equal(report, testCase.expectedReport) {
+				t.Errorf("report - got: %+v, want: %+v", report, testCase.expectedReport)
+			}
+			if report.IsComplete() {
+				t.Errorf("report must not be complete")
+			}
+			if testCase.expectedText != "" {
+				if diff := cmp.Diff(report.String(), testCase.expectedText); diff != "" {
+					t.Errorf("text - (-got +want):\n%v", diff)
+				}
+			}
+		})
+	}
+
+}
+
+func Test_Coverage_RecordComputation(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.Activate()
+
+	coverage, _ := NewCoverage(ns)
+	cp, _ := NewComputation(ns, NewContextWithoutData())
+	cp.Compute()
+	coverage.RecordComputation(cp)
+
+	expectedReport := CoverageReport{
+		Computations:      1,
+		Nodes:             3,
+		CoveredNodes:      2,
+		Branches:          2,
+		CoveredBranches:   1,
+		UncoveredNodes:    []string{"anotherActionNode"},
+		UncoveredBranches: []CoverageBranch{{Node: "alwaysTrueDecisionNode", Branch: false}},
+	}
+	if report := coverage.Report(); !cmp.Equal(report, expectedReport) {
+		t.Errorf("report - got: %+v, want: %+v", report, expectedReport)
+	}
+	page := coverage.HTML()
+	for _, expected := range []string{"class n1 covered", "class n2 uncovered", "linkStyle 1 stroke", "nodes: 2/3 covered"} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("html - missing: %v", expected)
+		}
+	}
+
+	_, err := NewCoverage(nil)
+	expectedError := errors.New("must have a node system to collect coverage")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Coverage_on_variants_and_failures(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(continueNode)
+	ns.AddNode(abortNode)
+	ns.AddLink(continueNode, abortNode)
+	ns.Activate()
+	variant := ns.Clone()
+	variant.Activate()
+
+	coverage, _ := NewCoverage(ns)
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureVariant("variant", variant)
+	eng.ConfigureRouting(PredicateRouting(func(data map[string]interface{}) bool {
+		return data["variant"] == true
+	}, "variant", ""))
+	eng.ConfigureCoverage(coverage)
+
+	eng.Compute(map[string]interface{}{"variant": true})
+	eng.Compute(map[string]interface{}{"variant": false})
+
+	expectedReport := CoverageReport{
+		Computations:   1,
+		Nodes:          2,
+		CoveredNodes:   1,
+		UncoveredNodes: []string{"abortNode"},
+	}
+	if report := coverage.Report(); !cmp.Equal(report, expectedReport) {
+		t.Errorf("report - got: %+v, want: %+v", report, expectedReport)
+	}
+}
@@ -0,0 +1,189 @@
+package hoff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Trigger give the times of the runs of a schedule.
+type Trigger interface {
+	// Next give the time of the first run after a time, or a zero time without more runs
+	Next(after time.Time) time.Time
+}
+
+// Every create a trigger of runs at a fixed interval.
+func Every(interval time.Duration) Trigger {
+	return intervalTrigger{interval: interval}
+}
+
+type intervalTrigger struct {
+	interval time.Duration
+}
+
+func (t intervalTrigger) Next(after time.Time) time.Time {
+	if t.interval <= 0 {
+		return time.Time{}
+	}
+	return after.Add(t.interval)
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron create a trigger of runs from a cron expression with 5 fields (minute, hour, day of month, month, and day of week),
+// each field being '*', a value, a range (1-5), a list (1,3,5), or a step (*/15, or 0-30/10).
+// The macros @yearly, @monthly, @weekly, @daily, and @hourly are supported too.
+// Like cron, a run happen when the day of month, or the day of week, match when both are restricted.
+func Cron(expression string) (Trigger, error) {
+	spec := strings.TrimSpace(expression)
+	if macro, found := cronMacros[spec]; found {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("can't parse cron expression without 5 fields: %v", expression)
+	}
+
+	trigger := cronTrigger{
+		domRestricted: fields[2] != "*",
+		dowRestricted: fields[4] != "*",
+	}
+	var err error
+	if trigger.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("can't parse cron minute field: %v", err)
+	}
+	if trigger.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("can't parse cron hour field: %v", err)
+	}
+	if trigger.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("can't parse cron day of month field: %v", err)
+	}
+	if trigger.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("can't parse cron month field: %v", err)
+	}
+	if trigger.weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("can't parse cron day of week field: %v", err)
+	}
+	if trigger.weekdays&(1<<7) != 0 {
+		trigger.weekdays |= 1
+	}
+	return trigger, nil
+}
+
+type cronTrigger struct {
+	minutes       uint64
+	hours         uint64
+	days          uint64
+	months        uint64
+	weekdays      uint64
+	domRestricted bool
+	dowRestricted bool
+}
+
+// Next give the first minute after a time matching the cron expression (in the location of the time),
+// or a zero time without match during the next 5 years.
+func (t cronTrigger) Next(after time.Time) time.Time {
+	next := after.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		year, month, day := next.Date()
+		location := next.Location()
+		switch {
+		case !haveBit(t.months, int(month)):
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+		case !t.matchDay(next):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, location)
+		case !haveBit(t.hours, next.Hour()):
+			next = time.Date(year, month, day, next.Hour()+1, 0, 0, 0, location)
+		case !haveBit(t.minutes, next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (t cronTrigger) matchDay(date time.Time) bool {
+	dom := haveBit(t.days, date.Day())
+	dow := haveBit(t.weekdays, int(date.Weekday()))
+	switch {
+	case t.domRestricted && t.dowRestricted:
+		return dom || dow
+	case t.domRestricted:
+		return dom
+	case t.dowRestricted:
+		return dow
+	}
+	return true
+}
+
+// parseCronField give the values of a cron field as bits.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		values, step := part, 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			values = part[:index]
+			parsed, err := strconv.Atoi(part[index+1:])
+			if err != nil || parsed < 1 {
+				return 0, fmt.Errorf("can't have step without positive number: %v", part)
+			}
+			step = parsed
+		}
+
+		low, high := min, max
+		switch {
+		case values == "*":
+		case strings.Contains(values, "-"):
+			bounds := strings.SplitN(values, "-", 2)
+			var err error
+			if low, err = parseCronValue(bounds[0], min, max); err != nil {
+				return 0, err
+			}
+			if high, err = parseCronValue(bounds[1], min, max); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("can't have range with reversed bounds: %v", values)
+			}
+		default:
+			value, err := parseCronValue(values, min, max)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if step == 1 {
+				high = value
+			}
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(text string, min, max int) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("can't have value who is not a number: %v", text)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("can't have value out of range %v-%v: %v", min, max, value)
+	}
+	return value, nil
+}
+
+func haveBit(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
@@ -0,0 +1,125 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Cron(t *testing.T) {
+	after := time.Date(2019, 1, 31, 10, 17, 30, 0, time.UTC) // a thursday
+
+	testCases := []struct {
+		name          string
+		givenCron     string
+		expectedNext  time.Time
+		expectedError error
+	}{
+		{
+			name:         "Next minute",
+			givenCron:    "* * * * *",
+			expectedNext: time.Date(2019, 1, 31, 10, 18, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next quarter of hour",
+			givenCron:    "*/15 * * * *",
+			expectedNext: time.Date(2019, 1, 31, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next night",
+			givenCron:    "30 2 * * *",
+			expectedNext: time.Date(2019, 2, 1, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next working day morning",
+			givenCron:    "0 8 * * 1-5",
+			expectedNext: time.Date(2019, 2, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next sunday with day of week 7",
+			givenCron:    "0 0 * * 7",
+			expectedNext: time.Date(2019, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next day of month, or day of week, with both restricted",
+			givenCron:    "0 0 15 * 6",
+			expectedNext: time.Date(2019, 2, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next 31st of a month",
+			givenCron:    "0 0 31 * *",
+			expectedNext: time.Date(2019, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next listed hours",
+			givenCron:    "0 6,12,18 * * *",
+			expectedNext: time.Date(2019, 1, 31, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next month",
+			givenCron:    "@monthly",
+			expectedNext: time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Next leap day",
+			givenCron:    "0 0 29 2 *",
+			expectedNext: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "No next time",
+			givenCron: "0 0 30 2 *",
+		},
+		{
+			name:          "Can't parse without 5 fields",
+			givenCron:     "* * * *",
+			expectedError: errors.New("can't parse cron expression without 5 fields: * * * *"),
+		},
+		{
+			name:          "Can't parse value out of range",
+			givenCron:     "60 * * * *",
+			expectedError: errors.New("can't parse cron minute field: can't have value out of range 0-59: 60"),
+		},
+		{
+			name:          "Can't parse value who is not a number",
+			givenCron:     "* * * JAN *",
+			expectedError: errors.New("can't parse cron month field: can't have value who is not a number: JAN"),
+		},
+		{
+			name:          "Can't parse reversed range",
+			givenCron:     "* 18-6 * * *",
+			expectedError: errors.New("can't parse cron hour field: can't have range with reversed bounds: 18-6"),
+		},
+		{
+			name:          "Can't parse step without positive number",
+			givenCron:     "*/0 * * * *",
+			expectedError: errors.New("can't parse cron minute field: can't have step without positive number: */0"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			trigger, err := Cron(testCase.givenCron)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if err != nil {
+				return
+			}
+			if next := trigger.Next(after); !next.Equal(testCase.expectedNext) {
+				t.Errorf("next - got: %v, want: %v", next, testCase.expectedNext)
+			}
+		})
+	}
+}
+
+func Test_Every(t *testing.T) {
+	after := time.Date(2019, 1, 31, 10, 17, 30, 0, time.UTC)
+	if next := Every(time.Hour).Next(after); !next.Equal(after.Add(time.Hour)) {
+		t.Errorf("next - got: %v, want: %v", next, after.Add(time.Hour))
+	}
+	if next := Every(0).Next(after); !next.IsZero() {
+		t.Errorf("next without interval - got: %v, want: zero time", next)
+	}
+}
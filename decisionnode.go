@@ -15,6 +15,11 @@ func (n DecisionNode) String() string {
 	return n.name
 }
 
+// ID give the name of the decision node as identifier.
+func (n *DecisionNode) ID() string {
+	return n.name
+}
+
 // Compute run the decision function and decide which compute state to return.
 func (n *DecisionNode) Compute(c *Context) ComputeState {
 	decision, err := n.decisionFunc(c)
@@ -34,6 +39,9 @@ func (n *DecisionNode) DecideCapability() bool {
 
 // NewDecisionNode create a DecisionNode based on a name and a function to take the needed decision.
 func NewDecisionNode(name string, decisionFunc func(*Context) (bool, error)) (*DecisionNode, error) {
+	if name == "" {
+		return nil, errors.New("can't create decision node without name")
+	}
 	if decisionFunc == nil {
 		return nil, errors.New("can't create decision node without function")
 	}
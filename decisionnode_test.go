@@ -75,3 +75,15 @@ func Test_DecisionNode_String(t *testing.T) {
 		t.Error("decision node must print its name")
 	}
 }
+
+func Test_NewDecisionNode_without_name(t *testing.T) {
+	node, err := NewDecisionNode("", func(*Context) (bool, error) { return true, nil })
+
+	expectedError := errors.New("can't create decision node without name")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+	if node != nil {
+		t.Errorf("decision node - got: %+v, want: <nil>", node)
+	}
+}
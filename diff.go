@@ -0,0 +1,258 @@
+package hoff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConfigureVersion set the version of the node system before activation (like "1.2.0", or a commit).
+func (s *NodeSystem) ConfigureVersion(version string) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't configure version, node system is freeze due to activation")
+	}
+	s.version = version
+	return true, nil
+}
+
+// Version get the configured version of the node system.
+func (s *NodeSystem) Version() string {
+	return s.version
+}
+
+// Hash give the content hash of the node system (a SHA-256 as hexadecimal).
+// Two node systems with the same nodes identifiers and kinds, links, and join modes
+// have the same hash, whatever their declaration order, or version.
+func (s *NodeSystem) Hash() string {
+	lines := make([]string, 0, len(s.nodes)+len(s.links))
+	for _, node := range s.nodes {
+		lines = append(lines, fmt.Sprintf("node %v %v %v", node.ID(), nodeKind(node), s.JoinModeOfNode(node)))
+	}
+	for _, link := range s.links {
+		lines = append(lines, fmt.Sprintf("link %v", newDiffLink(link)))
+	}
+	sort.Strings(lines)
+
+	hash := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// DiffLink is a link between two nodes identifiers, on a branch or not.
+type DiffLink struct {
+	From   string
+	To     string
+	Branch *bool
+}
+
+func newDiffLink(link NodeLink) DiffLink {
+	return DiffLink{
+		From:   link.From.ID(),
+		To:     link.To.ID(),
+		Branch: link.Branch,
+	}
+}
+
+func (l DiffLink) String() string {
+	if l.Branch == nil {
+		return fmt.Sprintf("%v -> %v", l.From, l.To)
+	}
+	return fmt.Sprintf("%v -> %v on %v", l.From, l.To, *l.Branch)
+}
+
+// BranchChange is a link between the same nodes identifiers whose branch have changed.
+type BranchChange struct {
+	From   string
+	To     string
+	Before *bool
+	After  *bool
+}
+
+// JoinModeChange is a node identifier whose join mode have changed.
+type JoinModeChange struct {
+	Node   string
+	Before JoinMode
+	After  JoinMode
+}
+
+// KindChange is a node identifier who have changed from action node to decision node, or the opposite.
+type KindChange struct {
+	Node   string
+	Before string
+	After  string
+}
+
+// NodeSystemDiff is the structural difference between two node systems,
+// based on the nodes identifiers.
+type NodeSystemDiff struct {
+	BeforeVersion    string
+	AfterVersion     string
+	AddedNodes       []string
+	RemovedNodes     []string
+	ChangedKinds     []KindChange
+	AddedLinks       []DiffLink
+	RemovedLinks     []DiffLink
+	ChangedBranches  []BranchChange
+	ChangedJoinModes []JoinModeChange
+}
+
+// Diff give the structural difference to go from a node system to another one,
+// a nil node system being an empty one.
+func Diff(before, after *NodeSystem) NodeSystemDiff {
+	if before == nil {
+		before = NewNodeSystem()
+	}
+	if after == nil {
+		after = NewNodeSystem()
+	}
+	diff := NodeSystemDiff{
+		BeforeVersion: before.version,
+		AfterVersion:  after.version,
+	}
+
+	for _, id := range sortedNodesIDs(after) {
+		afterNode := after.nodesByID[id]
+		beforeNode, found := before.nodesByID[id]
+		if !found {
+			diff.AddedNodes = append(diff.AddedNodes, id)
+			continue
+		}
+		if nodeKind(beforeNode) != nodeKind(afterNode) {
+			diff.ChangedKinds = append(diff.ChangedKinds, KindChange{Node: id, Before: nodeKind(beforeNode), After: nodeKind(afterNode)})
+		}
+		beforeMode, afterMode := before.JoinModeOfNode(beforeNode), after.JoinModeOfNode(afterNode)
+		if beforeMode != afterMode {
+			diff.ChangedJoinModes = append(diff.ChangedJoinModes, JoinModeChange{Node: id, Before: beforeMode, After: afterMode})
+		}
+	}
+	for _, id := range sortedNodesIDs(before) {
+		if _, found := after.nodesByID[id]; !found {
+			diff.RemovedNodes = append(diff.RemovedNodes, id)
+		}
+	}
+
+	removedLinks := linksDifference(before, after)
+	addedLinks := linksDifference(after, before)
+	for _, removed := range removedLinks {
+		if added, found := uniqueLinkBetween(addedLinks, removed.From, removed.To); found && len(linksBetween(removedLinks, removed.From, removed.To)) == 1 {
+			diff.ChangedBranches = append(diff.ChangedBranches, BranchChange{From: removed.From, To: removed.To, Before: removed.Branch, After: added.Branch})
+			continue
+		}
+		diff.RemovedLinks = append(diff.RemovedLinks, removed)
+	}
+	for _, added := range addedLinks {
+		if _, found := uniqueLinkBetween(removedLinks, added.From, added.To); found && len(linksBetween(addedLinks, added.From, added.To)) == 1 {
+			continue
+		}
+		diff.AddedLinks = append(diff.AddedLinks, added)
+	}
+	return diff
+}
+
+// IsEmpty tell if the two node systems have the same version, and structure.
+func (d NodeSystemDiff) IsEmpty() bool {
+	return d.BeforeVersion == d.AfterVersion && len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ChangedKinds) == 0 &&
+		len(d.AddedLinks) == 0 && len(d.RemovedLinks) == 0 && len(d.ChangedBranches) == 0 &&
+		len(d.ChangedJoinModes) == 0
+}
+
+// String render the difference as human-readable text, one change by line
+// (+ for an addition, - for a removal, and ~ for a change).
+func (d NodeSystemDiff) String() string {
+	var b strings.Builder
+	if d.BeforeVersion != d.AfterVersion {
+		fmt.Fprintf(&b, "~ version %v => %v\n", versionText(d.BeforeVersion), versionText(d.AfterVersion))
+	}
+	for _, id := range d.AddedNodes {
+		fmt.Fprintf(&b, "+ node %v\n", id)
+	}
+	for _, id := range d.RemovedNodes {
+		fmt.Fprintf(&b, "- node %v\n", id)
+	}
+	for _, change := range d.ChangedKinds {
+		fmt.Fprintf(&b, "~ node %v: %v => %v\n", change.Node, change.Before, change.After)
+	}
+	for _, link := range d.AddedLinks {
+		fmt.Fprintf(&b, "+ link %v\n", link)
+	}
+	for _, link := range d.RemovedLinks {
+		fmt.Fprintf(&b, "- link %v\n", link)
+	}
+	for _, change := range d.ChangedBranches {
+		fmt.Fprintf(&b, "~ link %v -> %v: branch %v => %v\n", change.From, change.To, branchText(change.Before), branchText(change.After))
+	}
+	for _, change := range d.ChangedJoinModes {
+		fmt.Fprintf(&b, "~ join mode of %v: %v => %v\n", change.Node, change.Before, change.After)
+	}
+	return b.String()
+}
+
+func nodeKind(n Node) string {
+	if n.DecideCapability() {
+		return "decision"
+	}
+	return "action"
+}
+
+func sortedNodesIDs(s *NodeSystem) []string {
+	ids := make([]string, 0, len(s.nodesByID))
+	for id := range s.nodesByID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// linksDifference give the sorted links of a node system who are not in another one.
+func linksDifference(s, o *NodeSystem) []DiffLink {
+	others := make(map[string]bool, len(o.links))
+	for _, link := range o.links {
+		others[newDiffLink(link).String()] = true
+	}
+	links := make([]DiffLink, 0)
+	for _, link := range s.links {
+		diffLink := newDiffLink(link)
+		if !others[diffLink.String()] {
+			links = append(links, diffLink)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].String() < links[j].String()
+	})
+	return links
+}
+
+func linksBetween(links []DiffLink, from, to string) []DiffLink {
+	between := make([]DiffLink, 0)
+	for _, link := range links {
+		if link.From == from && link.To == to {
+			between = append(between, link)
+		}
+	}
+	return between
+}
+
+// uniqueLinkBetween find the link between two nodes identifiers if it's the only one.
+func uniqueLinkBetween(links []DiffLink, from, to string) (DiffLink, bool) {
+	between := linksBetween(links, from, to)
+	if len(between) != 1 {
+		return DiffLink{}, false
+	}
+	return between[0], true
+}
+
+func versionText(version string) string {
+	if version == "" {
+		return "(none)"
+	}
+	return version
+}
+
+func branchText(branch *bool) string {
+	if branch == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%v", *branch)
+}
@@ -0,0 +1,202 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ConfigureVersion(t *testing.T) {
+	ns := NewNodeSystem()
+	if _, err := ns.ConfigureVersion("1.0.0"); err != nil {
+		t.Errorf("error - got: %+v", err)
+	}
+	ns.Activate()
+
+	_, err := ns.ConfigureVersion("1.1.0")
+	expectedError := errors.New("can't configure version, node system is freeze due to activation")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+	if ns.Version() != "1.0.0" || ns.Clone().Version() != "1.0.0" {
+		t.Errorf("version - got: %+v (clone %+v), want: %+v", ns.Version(), ns.Clone().Version(), "1.0.0")
+	}
+}
+
+func Test_NodeSystem_Hash(t *testing.T) {
+	a1, _ := NewActionNode("a1", func(*Context) error { return nil })
+	a2, _ := NewActionNode("a2", func(*Context) error { return nil })
+	otherA2, _ := NewActionNode("a2", func(*Context) error { return errors.New("other") })
+
+	system := func(version string, nodes []Node, modes map[Node]JoinMode, links []NodeLink) *NodeSystem {
+		ns := NewNodeSystem()
+		ns.ConfigureVersion(version)
+		loadNodeSystem(ns, nodes, modes, links)
+		return ns
+	}
+	hash := system("1.0.0", []Node{a1, a2}, nil, []NodeLink{newNodeLink(a1, a2)}).Hash()
+
+	testCases := []struct {
+		name         string
+		givenSystem  *NodeSystem
+		expectedSame bool
+	}{
+		{
+			name:         "Same hash on another version and declaration order",
+			givenSystem:  system("2.0.0", []Node{a2, a1}, nil, []NodeLink{newNodeLink(a1, a2)}),
+			expectedSame: true,
+		},
+		{
+			name:         "Same hash with nodes of the same identifiers",
+			givenSystem:  system("1.0.0", []Node{a1, otherA2}, nil, []NodeLink{newNodeLink(a1, otherA2)}),
+			expectedSame: true,
+		},
+		{
+			name:        "Other hash on another link",
+			givenSystem: system("1.0.0", []Node{a1, a2}, nil, []NodeLink{newNodeLink(a2, a1)}),
+		},
+		{
+			name:        "Other hash on another join mode",
+			givenSystem: system("1.0.0", []Node{a1, a2}, map[Node]JoinMode{a2: JoinOr}, []NodeLink{newNodeLink(a1, a2)}),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			same := testCase.givenSystem.Hash() == hash
+			if same != testCase.expectedSame {
+				t.Errorf("same hash - got: %+v, want: %+v", same, testCase.expectedSame)
+			}
+		})
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	a1, _ := NewActionNode("a1", func(*Context) error { return nil })
+	d2, _ := NewDecisionNode("d2", func(*Context) (bool, error) { return true, nil })
+	a3, _ := NewActionNode("a3", func(*Context) error { return nil })
+	a4, _ := NewActionNode("a4", func(*Context) error { return nil })
+	d4, _ := NewDecisionNode("a4", func(*Context) (bool, error) { return true, nil })
+	a5, _ := NewActionNode("a5", func(*Context) error { return nil })
+
+	system := func(version string, nodes []Node, modes map[Node]JoinMode, links []NodeLink) *NodeSystem {
+		ns := NewNodeSystem()
+		ns.ConfigureVersion(version)
+		loadNodeSystem(ns, nodes, modes, links)
+		return ns
+	}
+	before := system("1.0.0", []Node{a1, d2, a3, a4}, nil, []NodeLink{
+		newNodeLink(a1, d2),
+		newNodeLinkOnBranch(d2, a3, true),
+		newNodeLinkOnBranch(d2, a4, false),
+		newNodeLink(a1, a3),
+	})
+
+	testCases := []struct {
+		name         string
+		givenAfter   *NodeSystem
+		expectedDiff NodeSystemDiff
+		expectedText string
+	}{
+		{
+			name:       "No difference",
+			givenAfter: before.Clone(),
+			expectedDiff: NodeSystemDiff{
+				BeforeVersion: "1.0.0",
+				AfterVersion:  "1.0.0",
+			},
+		},
+		{
+			name: "Difference on nodes, links, branches, and join modes",
+			givenAfter: system("1.1.0", []Node{a1, d2, a3, d4, a5}, map[Node]JoinMode{a3: JoinOr}, []NodeLink{
+				newNodeLink(a1, d2),
+				newNodeLinkOnBranch(d2, a3, false),
+				newNodeLinkOnBranch(d2, d4, true),
+				newNodeLink(a1, a3),
+				newNodeLinkOnBranch(d4, a5, true),
+			}),
+			expectedDiff: NodeSystemDiff{
+				BeforeVersion:    "1.0.0",
+				AfterVersion:     "1.1.0",
+				AddedNodes:       []string{"a5"},
+				ChangedKinds:     []KindChange{{Node: "a4", Before: "action", After: "decision"}},
+				AddedLinks:       []DiffLink{{From: "a4", To: "a5", Branch: boolPointer(true)}},
+				ChangedBranches:  []BranchChange{{From: "d2", To: "a3", Before: boolPointer(true), After: boolPointer(false)}, {From: "d2", To: "a4", Before: boolPointer(false), After: boolPointer(true)}},
+				ChangedJoinModes: []JoinModeChange{{Node: "a3", Before: JoinNone, After: JoinOr}},
+			},
+			expectedText: "~ version 1.0.0 => 1.1.0\n" +
+				"+ node a5\n" +
+				"~ node a4: action => decision\n" +
+				"+ link a4 -> a5 on true\n" +
+				"~ link d2 -> a3: branch true => false\n" +
+				"~ link d2 -> a4: branch false => true\n" +
+				"~ join mode of a3: none => or\n",
+		},
+		{
+			name: "Difference on version only",
+			givenAfter: system("1.0.1", []Node{a1, d2, a3, a4}, nil, []NodeLink{
+				newNodeLink(a1, d2),
+				newNodeLinkOnBranch(d2, a3, true),
+				newNodeLinkOnBranch(d2, a4, false),
+				newNodeLink(a1, a3),
+			}),
+			expectedDiff: NodeSystemDiff{
+				BeforeVersion: "1.0.0",
+				AfterVersion:  "1.0.1",
+			},
+			expectedText: "~ version 1.0.0 => 1.0.1\n",
+		},
+		{
+			name:       "Difference on removed nodes, and links",
+			givenAfter: system("", []Node{a1, a3}, nil, []NodeLink{newNodeLink(a1, a3)}),
+			expectedDiff: NodeSystemDiff{
+				BeforeVersion: "1.0.0",
+				RemovedNodes:  []string{"a4", "d2"},
+				RemovedLinks: []DiffLink{
+					{From: "a1", To: "d2"},
+					{From: "d2", To: "a3", Branch: boolPointer(true)},
+					{From: "d2", To: "a4", Branch: boolPointer(false)},
+				},
+			},
+			expectedText: "~ version 1.0.0 => (none)\n" +
+				"- node a4\n" +
+				"- node d2\n" +
+				"- link a1 -> d2\n" +
+				"- link d2 -> a3 on true\n" +
+				"- link d2 -> a4 on false\n",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diff := Diff(before, testCase.givenAfter)
+
+			if !cmp.Equal(diff, testCase.expectedDiff) {
+				t.Errorf("diff - got: %+v, want: %+v", diff, testCase.expectedDiff)
+			}
+			if diff.IsEmpty() != (testCase.expectedText == "") {
+				t.Errorf("empty - got: %+v, want: %+v", diff.IsEmpty(), testCase.expectedText == "")
+			}
+			if diffText := cmp.Diff(diff.String(), testCase.expectedText); diffText != "" {
+				t.Errorf("text - (-got +want):\n%v", diffText)
+			}
+		})
+	}
+}
+
+func Test_Diff_nil_node_system(t *testing.T) {
+	a1, _ := NewActionNode("a1", func(*Context) error { return nil })
+	ns := NewNodeSystem()
+	ns.AddNode(a1)
+
+	added := Diff(nil, ns)
+	if !cmp.Equal(added, NodeSystemDiff{AddedNodes: []string{"a1"}}) {
+		t.Errorf("diff from nil - got: %+v", added)
+	}
+	removed := Diff(ns, nil)
+	if !cmp.Equal(removed, NodeSystemDiff{RemovedNodes: []string{"a1"}}) {
+		t.Errorf("diff to nil - got: %+v", removed)
+	}
+	if empty := Diff(nil, nil); !empty.IsEmpty() {
+		t.Errorf("diff of nils - got: %+v", empty)
+	}
+}
@@ -0,0 +1,158 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// computeStateJSON is the JSON representation of a ComputeState.
+type computeStateJSON struct {
+	Value  StateType `json:"state"`
+	Branch *bool     `json:"branch,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	Cached bool      `json:"cached,omitempty"`
+}
+
+// computationResultJSON is the JSON representation of a ComputationResult.
+// The report is keyed by node identifiers.
+type computationResultJSON struct {
+	Error   string                      `json:"error,omitempty"`
+	Data    map[string]interface{}      `json:"data"`
+	Report  map[string]computeStateJSON `json:"report,omitempty"`
+	Path    []string                    `json:"path,omitempty"`
+	Trace   []nodeTraceJSON             `json:"trace,omitempty"`
+	Version int                         `json:"version,omitempty"`
+	Variant string                      `json:"variant,omitempty"`
+}
+
+// nodeTraceJSON is the JSON representation of a NodeTrace.
+// The waited duration is written like "1.5s".
+type nodeTraceJSON struct {
+	Node string `json:"node"`
+	Wait string `json:"wait"`
+}
+
+// MarshalJSON encode a compute state with its state value, branch, error message, and cache hit.
+func (cs ComputeState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newComputeStateJSON(cs))
+}
+
+// UnmarshalJSON decode a compute state.
+// The error (if any) is decoded as an error with the same message.
+func (cs *ComputeState) UnmarshalJSON(data []byte) error {
+	var state computeStateJSON
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	*cs = state.computeState()
+	return nil
+}
+
+// MarshalJSON encode a computation result with its error message, data, report keyed by node identifiers, path of node identifiers, trace, version, and variant.
+func (r ComputationResult) MarshalJSON() ([]byte, error) {
+	result := computationResultJSON{
+		Data:    r.Data,
+		Version: r.Version,
+		Variant: r.Variant,
+	}
+	if r.Error != nil {
+		result.Error = r.Error.Error()
+	}
+	if r.Report != nil {
+		result.Report = make(map[string]computeStateJSON, len(r.Report))
+		for node, state := range r.Report {
+			result.Report[node.ID()] = newComputeStateJSON(state)
+		}
+	}
+	if r.Path != nil {
+		result.Path = make([]string, 0, len(r.Path))
+		for _, node := range r.Path {
+			result.Path = append(result.Path, node.ID())
+		}
+	}
+	for _, trace := range r.Trace {
+		result.Trace = append(result.Trace, nodeTraceJSON{Node: trace.Node.ID(), Wait: trace.Wait.String()})
+	}
+	return json.Marshal(result)
+}
+
+// UnmarshalComputationResult decode a computation result
+// whose report, path, and trace, nodes are found by their identifiers in the node system.
+func UnmarshalComputationResult(data []byte, system *NodeSystem) (ComputationResult, error) {
+	if system == nil {
+		return ComputationResult{}, errors.New("must have a node system to decode a computation result")
+	}
+	var result computationResultJSON
+	if err := json.Unmarshal(data, &result); err != nil {
+		return ComputationResult{}, err
+	}
+
+	decoded := ComputationResult{
+		Data:    result.Data,
+		Version: result.Version,
+		Variant: result.Variant,
+	}
+	if result.Error != "" {
+		decoded.Error = errors.New(result.Error)
+	}
+	if result.Report != nil {
+		decoded.Report = make(map[Node]ComputeState, len(result.Report))
+		for id, state := range result.Report {
+			node, found := system.NodeByID(id)
+			if !found {
+				return ComputationResult{}, fmt.Errorf("can't decode report of unknown node: %v", id)
+			}
+			decoded.Report[node] = state.computeState()
+		}
+	}
+	if result.Path != nil {
+		decoded.Path = make([]Node, 0, len(result.Path))
+		for _, id := range result.Path {
+			node, found := system.NodeByID(id)
+			if !found {
+				return ComputationResult{}, fmt.Errorf("can't decode path of unknown node: %v", id)
+			}
+			decoded.Path = append(decoded.Path, node)
+		}
+	}
+	for _, trace := range result.Trace {
+		node, found := system.NodeByID(trace.Node)
+		if !found {
+			return ComputationResult{}, fmt.Errorf("can't decode trace of unknown node: %v", trace.Node)
+		}
+		wait, err := time.ParseDuration(trace.Wait)
+		if err != nil {
+			return ComputationResult{}, fmt.Errorf("can't decode trace of node %v: %v", trace.Node, err)
+		}
+		decoded.Trace = append(decoded.Trace, NodeTrace{Node: node, Wait: wait})
+	}
+	return decoded, nil
+}
+
+func newComputeStateJSON(cs ComputeState) computeStateJSON {
+	state := computeStateJSON{
+		Value:  cs.Value,
+		Branch: cs.Branch,
+		Cached: cs.Cached,
+	}
+	if cs.Error != nil {
+		state.Error = cs.Error.Error()
+	}
+	return state
+}
+
+func (s computeStateJSON) computeState() ComputeState {
+	state := ComputeState{
+		Value:  s.Value,
+		Cached: s.Cached,
+	}
+	if s.Branch != nil {
+		state.Branch = boolPointer(*s.Branch)
+	}
+	if s.Error != "" {
+		state.Error = errors.New(s.Error)
+	}
+	return state
+}
@@ -0,0 +1,106 @@
+package hoff
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ComputeState_JSON(t *testing.T) {
+	testCases := []struct {
+		name         string
+		givenState   ComputeState
+		expectedJSON string
+	}{
+		{
+			name:         "Can encode a continue state",
+			givenState:   NewContinueComputeState(),
+			expectedJSON: `{"state":"Continue"}`,
+		},
+		{
+			name:         "Can encode a continue state on a branch",
+			givenState:   NewContinueOnBranchComputeState(false),
+			expectedJSON: `{"state":"Continue","branch":false}`,
+		},
+		{
+			name:         "Can encode an abort state",
+			givenState:   NewAbortComputeState(errors.New("action error")),
+			expectedJSON: `{"state":"Abort","error":"action error"}`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			encoded, err := json.Marshal(testCase.givenState)
+			if err != nil {
+				t.Fatalf("encoding error - got: %+v", err)
+			}
+			if string(encoded) != testCase.expectedJSON {
+				t.Errorf("json - got: %v, want: %v", string(encoded), testCase.expectedJSON)
+			}
+
+			var decoded ComputeState
+			if err := json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("decoding error - got: %+v", err)
+			}
+			if !cmp.Equal(decoded, testCase.givenState, errorComparator) {
+				t.Errorf("state - got: %+v, want: %+v", decoded, testCase.givenState)
+			}
+		})
+	}
+}
+
+func Test_ComputationResult_JSON(t *testing.T) {
+	throwedError := errors.New("action error")
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.Activate()
+
+	result := ComputationResult{
+		Error: throwedError,
+		Data:  map[string]interface{}{"key": "value"},
+		Report: map[Node]ComputeState{
+			alwaysTrueDecisionNode: NewContinueOnBranchComputeState(true),
+			someActionNode:         NewAbortComputeState(throwedError),
+			anotherActionNode:      NewSkipComputeState(),
+		},
+		Path:    []Node{alwaysTrueDecisionNode, someActionNode},
+		Trace:   []NodeTrace{{Node: someActionNode, Wait: 1500 * time.Millisecond}},
+		Version: 2,
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("encoding error - got: %+v", err)
+	}
+	expectedJSON := `{"error":"action error","data":{"key":"value"},"report":{"alwaysTrueDecisionNode":{"state":"Continue","branch":true},"anotherActionNode":{"state":"Skip"},"someActionNode":{"state":"Abort","error":"action error"}},"path":["alwaysTrueDecisionNode","someActionNode"],"trace":[{"node":"someActionNode","wait":"1.5s"}],"version":2}`
+	if string(encoded) != expectedJSON {
+		t.Errorf("json - got: %v, want: %v", string(encoded), expectedJSON)
+	}
+
+	decoded, err := UnmarshalComputationResult(encoded, ns)
+	if err != nil {
+		t.Fatalf("decoding error - got: %+v", err)
+	}
+	if !cmp.Equal(decoded, result, NodeComparator, errorComparator) {
+		t.Errorf("result - got: %+v, want: %+v", decoded, result)
+	}
+
+	_, err = UnmarshalComputationResult([]byte(`{"data":{},"report":{"unknownNode":{"state":"Skip"}}}`), ns)
+	expectedError := errors.New("can't decode report of unknown node: unknownNode")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	_, err = UnmarshalComputationResult([]byte(`{"data":{},"trace":[{"node":"someActionNode","wait":"soon"}]}`), ns)
+	expectedError = errors.New(`can't decode trace of node someActionNode: time: invalid duration "soon"`)
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -1,25 +1,59 @@
 package hoff
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNodeSystemNotConfigured is the error of a computation on an engine without node system.
+	ErrNodeSystemNotConfigured = errors.New("need a configured node system")
 )
 
 // Engine expose an engine to manage multiple computations based on a node system.
 type Engine struct {
-	mode   ComputationMode
-	system *NodeSystem
+	mode ComputationMode
+
+	systemMutex sync.RWMutex
+	system      *NodeSystem
+	version     int
+
+	variants     map[string]*NodeSystem
+	routing      RoutingPolicy
+	shadow       string
+	shadowReport func(ShadowReport)
+	coverage     *Coverage
+
+	concurrency int
+	ordered     bool
+
+	jobsMutex    sync.Mutex
+	jobs         JobStore
+	jobRetention time.Duration
+	jobCancels   map[string]context.CancelFunc
+	unsavedJobs  map[string]Job
+	queue        *jobQueue
 }
 
 // NewEngine create an engine with computation mode.
 // Need to be configured with a node system
 func NewEngine(mode ComputationMode) *Engine {
 	return &Engine{
-		mode: mode,
+		mode:         mode,
+		variants:     make(map[string]*NodeSystem),
+		jobs:         NewMemoryJobStore(),
+		jobRetention: DefaultJobRetention,
+		jobCancels:   make(map[string]context.CancelFunc),
+		unsavedJobs:  make(map[string]Job),
 	}
 }
 
 // ConfigureNodeSystem add a node system to the engine (only once).
 func (e *Engine) ConfigureNodeSystem(system *NodeSystem) error {
+	e.systemMutex.Lock()
+	defer e.systemMutex.Unlock()
 	if e.system != nil {
 		return errors.New("node system already configured")
 	}
@@ -27,31 +61,106 @@ func (e *Engine) ConfigureNodeSystem(system *NodeSystem) error {
 		return errors.New("node system need to be activated")
 	}
 	e.system = system
+	e.version = 1
 	return nil
 }
 
+// SwapNodeSystem replace atomically the node system of the engine, and return its new version.
+// The running computations finish on the previous node system, and the new ones use the new node system.
+func (e *Engine) SwapNodeSystem(system *NodeSystem) (int, error) {
+	e.systemMutex.Lock()
+	defer e.systemMutex.Unlock()
+	if system == nil || !system.IsActivated() {
+		return e.version, errors.New("node system need to be activated")
+	}
+	e.system = system
+	e.version++
+	return e.version, nil
+}
+
+// NodeSystem get the configured node system.
+func (e *Engine) NodeSystem() *NodeSystem {
+	system, _ := e.currentNodeSystem()
+	return system
+}
+
+// Version get the version of the configured node system,
+// starting at 1 when configured, and incremented on each swap (0 without node system).
+func (e *Engine) Version() int {
+	_, version := e.currentNodeSystem()
+	return version
+}
+
+func (e *Engine) currentNodeSystem() (*NodeSystem, int) {
+	e.systemMutex.RLock()
+	defer e.systemMutex.RUnlock()
+	return e.system, e.version
+}
+
 // Compute run computation against node system with input data.
 func (e *Engine) Compute(data map[string]interface{}) ComputationResult {
-	if e.system == nil {
+	return e.ComputeContext(context.Background(), data)
+}
+
+// ComputeContext run computation against node system (or the routed variant) with input data
+// until the context is done (cancelled, or timed out).
+func (e *Engine) ComputeContext(ctx context.Context, data map[string]interface{}) ComputationResult {
+	route := e.route(data)
+	var shadowData map[string]interface{}
+	if route.shadow != nil {
+		// the shadow computation never share a nested value with the computation
+		shadowData = deepCopyData(data)
+	}
+
+	result := compute(ctx, route.system, data)
+	result.Version = route.version
+	result.Variant = route.variant
+	if route.coverage != nil && result.Error != ErrNodeSystemNotConfigured {
+		route.coverage.Record(result)
+	}
+	if route.shadow != nil && result.Error != ErrNodeSystemNotConfigured {
+		go route.runShadow(shadowData, deepCopyResult(result))
+	}
+	return result
+}
+
+func compute(ctx context.Context, system *NodeSystem, data map[string]interface{}) ComputationResult {
+	if system == nil {
 		return ComputationResult{
 			Data:  data,
-			Error: errors.New("need a configured node system"),
+			Error: ErrNodeSystemNotConfigured,
 		}
 	}
 
-	cp, _ := NewComputation(e.system, NewContext(data))
+	cp, _ := NewComputation(system, NewContext(data))
 
-	err := cp.Compute()
+	err := cp.ComputeContext(ctx)
 	return ComputationResult{
 		Data:   cp.Context.Data,
 		Error:  err,
 		Report: cp.Report,
+		Path:   cp.Path,
+		Trace:  cp.Trace,
 	}
 }
 
-// ComputationResult store the result of a computation.
+// ComputationResult store the result of a computation (with the computed nodes in order in the Path,
+// and the durations waited by the nodes with limits in the Trace), and the version of the engine node system (or the variant) used by it.
 type ComputationResult struct {
-	Error  error
-	Data   map[string]interface{}
-	Report map[Node]ComputeState
+	Error   error
+	Data    map[string]interface{}
+	Report  map[Node]ComputeState
+	Path    []Node
+	Trace   []NodeTrace
+	Version int
+	Variant string
+}
+
+// ReportByID give the compute state of each node keyed by the node identifier.
+func (r ComputationResult) ReportByID() map[string]ComputeState {
+	report := make(map[string]ComputeState, len(r.Report))
+	for node, state := range r.Report {
+		report[node.ID()] = state
+	}
+	return report
 }
@@ -100,6 +100,8 @@ func Test_Engine_Compute(t *testing.T) {
 					stringAction: NewSkipComputeState(),
 					throwError:   NewAbortComputeState(throwedError),
 				},
+				Path:    []Node{keyIsPresent, throwError},
+				Version: 1,
 			},
 		},
 		{
@@ -117,6 +119,8 @@ func Test_Engine_Compute(t *testing.T) {
 					stringAction: NewContinueComputeState(),
 					throwError:   NewSkipComputeState(),
 				},
+				Path:    []Node{keyIsPresent, stringAction},
+				Version: 1,
 			},
 		},
 	}
@@ -131,6 +135,23 @@ func Test_Engine_Compute(t *testing.T) {
 	}
 }
 
+func Test_ComputationResult_ReportByID(t *testing.T) {
+	result := ComputationResult{
+		Report: map[Node]ComputeState{
+			someActionNode:    NewContinueComputeState(),
+			anotherActionNode: NewSkipComputeState(),
+		},
+	}
+
+	expectedReport := map[string]ComputeState{
+		"someActionNode":    NewContinueComputeState(),
+		"anotherActionNode": NewSkipComputeState(),
+	}
+	if !cmp.Equal(result.ReportByID(), expectedReport) {
+		t.Errorf("got: %+v, want: %+v", result.ReportByID(), expectedReport)
+	}
+}
+
 func Test_UnconfiguredEngine_Compute(t *testing.T) {
 	eng := NewEngine(SequentialComputation)
 	data := make(map[string]interface{})
@@ -147,7 +168,73 @@ func Test_UnconfiguredEngine_Compute(t *testing.T) {
 }
 
 var (
-	engineComparator = cmp.Comparer(func(x, y Engine) bool {
+	engineComparator = cmp.Comparer(func(x, y *Engine) bool {
 		return x.mode == y.mode && ((x.system == nil && y.system == nil) || (x.system != nil && y.system != nil && cmp.Equal(x.system, y.system)))
 	})
 )
+
+func Test_Engine_SwapNodeSystem(t *testing.T) {
+	startedAction := make(chan struct{})
+	releaseAction := make(chan struct{})
+	waitAction, _ := NewActionNode("waitAction", func(c *Context) error {
+		close(startedAction)
+		<-releaseAction
+		c.Store("system", "first")
+		return nil
+	})
+	storeAction, _ := NewActionNode("storeAction", func(c *Context) error {
+		c.Store("system", "second")
+		return nil
+	})
+
+	firstSystem := NewNodeSystem()
+	firstSystem.AddNode(waitAction)
+	firstSystem.Activate()
+	secondSystem := NewNodeSystem()
+	secondSystem.AddNode(storeAction)
+	secondSystem.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(firstSystem)
+
+	firstResult := make(chan ComputationResult)
+	go func() {
+		firstResult <- eng.Compute(map[string]interface{}{})
+	}()
+	<-startedAction
+
+	version, err := eng.SwapNodeSystem(secondSystem)
+	if err != nil || version != 2 {
+		t.Errorf("swap - got: %+v (%+v), want: %+v", version, err, 2)
+	}
+	secondResult := eng.Compute(map[string]interface{}{})
+	close(releaseAction)
+
+	expectedFirstResult := ComputationResult{
+		Data:    map[string]interface{}{"system": "first"},
+		Report:  map[Node]ComputeState{waitAction: NewContinueComputeState()},
+		Path:    []Node{waitAction},
+		Version: 1,
+	}
+	if result := <-firstResult; !cmp.Equal(result, expectedFirstResult, NodeComparator, errorComparator) {
+		t.Errorf("in-flight result - got: %+v, want: %+v", result, expectedFirstResult)
+	}
+	expectedSecondResult := ComputationResult{
+		Data:    map[string]interface{}{"system": "second"},
+		Report:  map[Node]ComputeState{storeAction: NewContinueComputeState()},
+		Path:    []Node{storeAction},
+		Version: 2,
+	}
+	if !cmp.Equal(secondResult, expectedSecondResult, NodeComparator, errorComparator) {
+		t.Errorf("new result - got: %+v, want: %+v", secondResult, expectedSecondResult)
+	}
+
+	version, err = eng.SwapNodeSystem(NewNodeSystem())
+	expectedError := errors.New("node system need to be activated")
+	if version != 2 || !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("swap unactivated - got: %+v (%+v), want: %+v (%+v)", version, err, 2, expectedError)
+	}
+	if eng.NodeSystem() != secondSystem || eng.Version() != 2 {
+		t.Errorf("node system - got: %+v (version %+v), want: %+v (version %+v)", eng.NodeSystem(), eng.Version(), secondSystem, 2)
+	}
+}
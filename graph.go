@@ -0,0 +1,62 @@
+package hoff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT render the nodes and links of the node system as a graphviz digraph.
+// Decision nodes are drawn as diamonds, and links on a branch are labeled with it.
+func (s *NodeSystem) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	for _, node := range s.nodes {
+		shape := "box"
+		if node.DecideCapability() {
+			shape = "diamond"
+		}
+		label := node.ID()
+		if mode := s.JoinModeOfNode(node); mode != JoinNone {
+			label = fmt.Sprintf("%v (%v)", label, mode)
+		}
+		fmt.Fprintf(&b, "\t%q [label=%q shape=%v];\n", node.ID(), label, shape)
+	}
+	for _, link := range s.links {
+		branch := ""
+		if link.Branch != nil {
+			branch = fmt.Sprintf(" [label=\"%v\"]", *link.Branch)
+		}
+		fmt.Fprintf(&b, "\t%q -> %q%v;\n", link.From.ID(), link.To.ID(), branch)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid render the nodes and links of the node system as a mermaid flowchart.
+// Decision nodes are drawn as rhombus, and links on a branch are labeled with it.
+func (s *NodeSystem) Mermaid() string {
+	ids := make(map[Node]string)
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for i, node := range s.nodes {
+		ids[node] = fmt.Sprintf("n%v", i)
+		label := node.ID()
+		if mode := s.JoinModeOfNode(node); mode != JoinNone {
+			label = fmt.Sprintf("%v (%v)", label, mode)
+		}
+		label = strings.Replace(label, "\"", "#quot;", -1)
+		if node.DecideCapability() {
+			fmt.Fprintf(&b, "\t%v{\"%v\"}\n", ids[node], label)
+		} else {
+			fmt.Fprintf(&b, "\t%v[\"%v\"]\n", ids[node], label)
+		}
+	}
+	for _, link := range s.links {
+		branch := ""
+		if link.Branch != nil {
+			branch = fmt.Sprintf("|%v|", *link.Branch)
+		}
+		fmt.Fprintf(&b, "\t%v -->%v %v\n", ids[link.From], branch, ids[link.To])
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package hoff
+
+import (
+	"testing"
+)
+
+func graphNodeSystem() *NodeSystem {
+	ns := NewNodeSystem()
+	ns.AddNode(alwaysTrueDecisionNode)
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true)
+	ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.ConfigureJoinModeOnNode(anotherActionNode, JoinOr)
+	return ns
+}
+
+func Test_NodeSystem_DOT(t *testing.T) {
+	expectedDOT := `digraph {
+	"alwaysTrueDecisionNode" [label="alwaysTrueDecisionNode" shape=diamond];
+	"someActionNode" [label="someActionNode" shape=box];
+	"anotherActionNode" [label="anotherActionNode (or)" shape=box];
+	"alwaysTrueDecisionNode" -> "someActionNode" [label="true"];
+	"alwaysTrueDecisionNode" -> "anotherActionNode" [label="false"];
+	"someActionNode" -> "anotherActionNode";
+}
+`
+
+	dot := graphNodeSystem().DOT()
+	if dot != expectedDOT {
+		t.Errorf("got: %v, want: %v", dot, expectedDOT)
+	}
+}
+
+func Test_NodeSystem_Mermaid(t *testing.T) {
+	expectedMermaid := `flowchart TD
+	n0{"alwaysTrueDecisionNode"}
+	n1["someActionNode"]
+	n2["anotherActionNode (or)"]
+	n0 -->|true| n1
+	n0 -->|false| n2
+	n1 --> n2
+`
+
+	mermaid := graphNodeSystem().Mermaid()
+	if mermaid != expectedMermaid {
+		t.Errorf("got: %v, want: %v", mermaid, expectedMermaid)
+	}
+}
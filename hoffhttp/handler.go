@@ -0,0 +1,162 @@
+/*
+Package hoffhttp expose a hoff Engine as a REST service.
+
+Create a handler on a configured engine and serve it:
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	h := hoffhttp.NewHandler(eng)
+	h.ConfigureTimeout(5 * time.Second)
+	http.ListenAndServe(":8080", h)
+
+The handler serve the following endpoints:
+
+	POST /compute  compute the JSON object of the body, and respond the computation result as JSON
+	GET  /graph    respond the graph of the node system (format=dot by default, or format=mermaid)
+	GET  /health   respond the health of the service
+*/
+package hoffhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rlespinasse/hoff"
+)
+
+// Handler is a http.Handler who run the computations of an engine.
+type Handler struct {
+	engine  *hoff.Engine
+	timeout time.Duration
+	mux     *http.ServeMux
+}
+
+// NewHandler create a handler based on an engine, without timeout on computations.
+func NewHandler(engine *hoff.Engine) *Handler {
+	h := &Handler{
+		engine: engine,
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("/compute", h.compute)
+	h.mux.HandleFunc("/graph", h.graph)
+	h.mux.HandleFunc("/health", h.health)
+	return h
+}
+
+// ConfigureTimeout set the maximum duration of a computation (no timeout at 0).
+func (h *Handler) ConfigureTimeout(timeout time.Duration) {
+	h.timeout = timeout
+}
+
+// ServeHTTP dispatch the request to the endpoints.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) compute(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("can't %v on /compute", r.Method))
+		return
+	}
+
+	var data map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("can't decode body as JSON object: %v", err))
+		return
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	ctx := r.Context()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+	result := h.engine.ComputeContext(ctx, data)
+	writeJSON(w, resultStatus(result), result)
+}
+
+func (h *Handler) graph(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("can't %v on /graph", r.Method))
+		return
+	}
+	system := h.engine.NodeSystem()
+	if system == nil {
+		writeError(w, http.StatusServiceUnavailable, hoff.ErrNodeSystemNotConfigured)
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		fmt.Fprint(w, system.DOT())
+	case "mermaid":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, system.Mermaid())
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("can't render graph on unknown format '%v'", format))
+	}
+}
+
+func (h *Handler) health(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("can't %v on /health", r.Method))
+		return
+	}
+	if h.engine.NodeSystem() == nil {
+		writeError(w, http.StatusServiceUnavailable, hoff.ErrNodeSystemNotConfigured)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// resultStatus give the status code of a computation result.
+// An aborted computation is an unprocessable entity, a rejected one is too many requests
+// (or service unavailable with an open circuit), and a timed out computation is a gateway timeout.
+func resultStatus(result hoff.ComputationResult) int {
+	switch {
+	case result.Error == nil:
+		return http.StatusOK
+	case errors.Is(result.Error, hoff.ErrNodeSystemNotConfigured):
+		return http.StatusServiceUnavailable
+	case errors.Is(result.Error, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(result.Error, context.Canceled):
+		return http.StatusServiceUnavailable
+	case errors.As(result.Error, &hoff.CircuitOpenError{}):
+		return http.StatusServiceUnavailable
+	case errors.As(result.Error, &hoff.RateLimitError{}):
+		return http.StatusTooManyRequests
+	}
+	// a rejected node take precedence over an aborted one, whatever the report order
+	rejected, aborted := false, false
+	for _, state := range result.Report {
+		rejected = rejected || state.Value == hoff.RejectState
+		aborted = aborted || state.Value == hoff.AbortState
+	}
+	switch {
+	case rejected:
+		return http.StatusTooManyRequests
+	case aborted:
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,273 @@
+package hoffhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rlespinasse/hoff"
+)
+
+func testEngine() *hoff.Engine {
+	keyIsPresent, _ := hoff.NewDecisionNode("keyIsPresent", func(c *hoff.Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	storeFound, _ := hoff.NewActionNode("storeFound", func(c *hoff.Context) error {
+		c.Store("found", true)
+		return nil
+	})
+	slowAction, _ := hoff.NewActionNode("slowAction", func(c *hoff.Context) error {
+		if c.HaveKey("slow") {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil
+	})
+	throwError, _ := hoff.NewActionNode("throwError", func(c *hoff.Context) error {
+		return errors.New("missing 'key' in context")
+	})
+
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(slowAction)
+	ns.AddNode(storeFound)
+	ns.AddNode(throwError)
+	ns.AddLinkOnBranch(keyIsPresent, slowAction, true)
+	ns.AddLink(slowAction, storeFound)
+	ns.AddLinkOnBranch(keyIsPresent, throwError, false)
+	ns.Activate()
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	return eng
+}
+
+func rateLimitedEngine() *hoff.Engine {
+	callPartner, _ := hoff.NewActionNode("callPartner", func(c *hoff.Context) error {
+		return nil
+	})
+
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(callPartner)
+	ns.ConfigureRateLimit(callPartner, hoff.RateLimit{Rate: 0.001, Burst: 1, Mode: hoff.RateLimitReject})
+	ns.Activate()
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	return eng
+}
+
+func circuitBreakerEngine() *hoff.Engine {
+	callPartner, _ := hoff.NewActionNode("callPartner", func(c *hoff.Context) error {
+		return errors.New("partner is down")
+	})
+
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(callPartner)
+	ns.ConfigureCircuitBreaker(callPartner, hoff.CircuitBreaker{Failures: 1, Window: time.Minute, OpenDuration: time.Minute})
+	ns.Activate()
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	return eng
+}
+
+func Test_Handler(t *testing.T) {
+	h := NewHandler(testEngine())
+	h.ConfigureTimeout(10 * time.Millisecond)
+	limited := NewHandler(rateLimitedEngine())
+	broken := NewHandler(circuitBreakerEngine())
+
+	testCases := []struct {
+		name           string
+		givenHandler   http.Handler
+		givenMethod    string
+		givenTarget    string
+		givenBody      string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Can compute data",
+			givenHandler:   h,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{"key": "value"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":{"found":true,"key":"value"},"report":{"keyIsPresent":{"state":"Continue","branch":true},"slowAction":{"state":"Continue"},"storeFound":{"state":"Continue"},"throwError":{"state":"Skip"}},"path":["keyIsPresent","slowAction","storeFound"],"version":1}`,
+		},
+		{
+			name:           "Can compute an aborted computation",
+			givenHandler:   h,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"missing 'key' in context","data":{},"report":{"keyIsPresent":{"state":"Continue","branch":false},"slowAction":{"state":"Skip"},"storeFound":{"state":"Skip"},"throwError":{"state":"Abort","error":"missing 'key' in context"}},"path":["keyIsPresent","throwError"],"version":1}`,
+		},
+		{
+			name:           "Can compute a timed out computation",
+			givenHandler:   h,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{"key": "value", "slow": true}`,
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   `{"error":"context deadline exceeded","data":{"key":"value","slow":true},"report":{"keyIsPresent":{"state":"Continue","branch":true},"slowAction":{"state":"Continue"}},"path":["keyIsPresent","slowAction"],"version":1}`,
+		},
+		{
+			name:           "Can compute under the rate limit",
+			givenHandler:   limited,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":{},"report":{"callPartner":{"state":"Continue"}},"path":["callPartner"],"trace":[{"node":"callPartner","wait":"0s"}],"version":1}`,
+		},
+		{
+			name:           "Can compute a rejected computation over the rate limit",
+			givenHandler:   limited,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{}`,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `{"error":"can't compute node over its rate limit: callPartner","data":{},"report":{"callPartner":{"state":"Reject","error":"can't compute node over its rate limit: callPartner"}},"path":["callPartner"],"trace":[{"node":"callPartner","wait":"0s"}],"version":1}`,
+		},
+		{
+			name:           "Can compute an aborted computation opening the circuit",
+			givenHandler:   broken,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"error":"partner is down","data":{},"report":{"callPartner":{"state":"Abort","error":"partner is down"}},"path":["callPartner"],"version":1}`,
+		},
+		{
+			name:           "Can compute a rejected computation with open circuit",
+			givenHandler:   broken,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{}`,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"can't compute node with open circuit: callPartner","data":{},"report":{"callPartner":{"state":"Reject","error":"can't compute node with open circuit: callPartner"}},"path":["callPartner"],"version":1}`,
+		},
+		{
+			name:           "Can't compute an invalid body",
+			givenHandler:   h,
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `["key"]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"can't decode body as JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}"}`,
+		},
+		{
+			name:           "Can't compute without node system",
+			givenHandler:   NewHandler(hoff.NewEngine(hoff.SequentialComputation)),
+			givenMethod:    http.MethodPost,
+			givenTarget:    "/compute",
+			givenBody:      `{}`,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"need a configured node system","data":{}}`,
+		},
+		{
+			name:           "Can't compute on GET",
+			givenHandler:   h,
+			givenMethod:    http.MethodGet,
+			givenTarget:    "/compute",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   `{"error":"can't GET on /compute"}`,
+		},
+		{
+			name:           "Can get the graph",
+			givenHandler:   h,
+			givenMethod:    http.MethodGet,
+			givenTarget:    "/graph?format=mermaid",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "flowchart TD\n\tn0{\"keyIsPresent\"}\n\tn1[\"slowAction\"]\n\tn2[\"storeFound\"]\n\tn3[\"throwError\"]\n\tn0 -->|true| n1\n\tn1 --> n2\n\tn0 -->|false| n3",
+		},
+		{
+			name:           "Can get the health",
+			givenHandler:   h,
+			givenMethod:    http.MethodGet,
+			givenTarget:    "/health",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok"}`,
+		},
+		{
+			name:           "Can get the health without node system",
+			givenHandler:   NewHandler(hoff.NewEngine(hoff.SequentialComputation)),
+			givenMethod:    http.MethodGet,
+			givenTarget:    "/health",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"need a configured node system"}`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.givenMethod, testCase.givenTarget, strings.NewReader(testCase.givenBody))
+			response := httptest.NewRecorder()
+			testCase.givenHandler.ServeHTTP(response, request)
+
+			if response.Code != testCase.expectedStatus {
+				t.Errorf("status - got: %+v, want: %+v", response.Code, testCase.expectedStatus)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(response.Body.String()), testCase.expectedBody); diff != "" {
+				t.Errorf("body - (-got +want):\n%v", diff)
+			}
+		})
+	}
+}
+
+func Test_resultStatus(t *testing.T) {
+	rejecting, _ := hoff.NewActionNode("rejecting", func(c *hoff.Context) error { return nil })
+	aborting, _ := hoff.NewActionNode("aborting", func(c *hoff.Context) error { return nil })
+
+	testCases := []struct {
+		name           string
+		givenResult    hoff.ComputationResult
+		expectedStatus int
+	}{
+		{
+			name:           "Can respond too many requests on rate limit error",
+			givenResult:    hoff.ComputationResult{Error: hoff.RateLimitError{Node: rejecting}},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "Can respond service unavailable on open circuit error",
+			givenResult:    hoff.ComputationResult{Error: hoff.CircuitOpenError{Node: rejecting}},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "Can respond too many requests on rejected and aborted nodes",
+			givenResult: hoff.ComputationResult{
+				Error: errors.New("failure"),
+				Report: map[hoff.Node]hoff.ComputeState{
+					rejecting: hoff.NewRejectComputeState(errors.New("rejected")),
+					aborting:  hoff.NewAbortComputeState(errors.New("aborted")),
+				},
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "Can respond unprocessable entity on aborted node",
+			givenResult: hoff.ComputationResult{
+				Error: errors.New("failure"),
+				Report: map[hoff.Node]hoff.ComputeState{
+					aborting: hoff.NewAbortComputeState(errors.New("aborted")),
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				if status := resultStatus(testCase.givenResult); status != testCase.expectedStatus {
+					t.Fatalf("status - got: %+v, want: %+v", status, testCase.expectedStatus)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package hofftest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rlespinasse/hoff"
+)
+
+// AssertPath check that the computed nodes of a result are the expected nodes identifiers, in order.
+func AssertPath(t testing.TB, result hoff.ComputationResult, ids ...string) {
+	t.Helper()
+	path := make([]string, 0, len(result.Path))
+	for _, node := range result.Path {
+		path = append(path, node.ID())
+	}
+	if !cmp.Equal(path, ids) {
+		t.Errorf("path - got: %v, want: %v", path, ids)
+	}
+}
+
+// AssertSkipped check that the nodes of the identifiers are skipped in the report of a result.
+func AssertSkipped(t testing.TB, result hoff.ComputationResult, ids ...string) {
+	t.Helper()
+	report := result.ReportByID()
+	for _, id := range ids {
+		state, found := report[id]
+		if !found || state.Value != hoff.SkipState {
+			t.Errorf("state of %v - got: %v, want: %v", id, stateText(state, found), hoff.NewSkipComputeState())
+		}
+	}
+}
+
+// AssertNotReached check that the nodes of the identifiers are not in the report of a result.
+func AssertNotReached(t testing.TB, result hoff.ComputationResult, ids ...string) {
+	t.Helper()
+	report := result.ReportByID()
+	for _, id := range ids {
+		if state, found := report[id]; found {
+			t.Errorf("state of %v - got: %v, want: not reached", id, state)
+		}
+	}
+}
+
+// AssertBranch check that a decision node of a result have continued on a branch.
+func AssertBranch(t testing.TB, result hoff.ComputationResult, id string, branch bool) {
+	t.Helper()
+	state, found := result.ReportByID()[id]
+	if !found || state.Value != hoff.ContinueState || state.Branch == nil || *state.Branch != branch {
+		t.Errorf("state of %v - got: %v, want: %v", id, stateText(state, found), hoff.NewContinueOnBranchComputeState(branch))
+	}
+}
+
+// AssertAbortedAt check that the computation of a result is aborted by the node of the identifier.
+func AssertAbortedAt(t testing.TB, result hoff.ComputationResult, id string) {
+	t.Helper()
+	state, found := result.ReportByID()[id]
+	if !found || state.Value != hoff.AbortState {
+		t.Errorf("state of %v - got: %v, want: %v", id, stateText(state, found), hoff.AbortState)
+		return
+	}
+	if result.Error == nil || state.Error == nil || result.Error.Error() != state.Error.Error() {
+		t.Errorf("error - got: %v, want: %v", result.Error, state.Error)
+	}
+}
+
+// AssertContextContains check that the data of a result contains a key with an expected value,
+// the values being compared deeply (unexported fields included).
+func AssertContextContains(t testing.TB, result hoff.ComputationResult, key string, expected interface{}) {
+	t.Helper()
+	value, found := result.Data[key]
+	if !found {
+		t.Errorf("context - missing key: %v", key)
+		return
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("context value of %v - got: %#v, want: %#v", key, value, expected)
+	}
+}
+
+func stateText(state hoff.ComputeState, found bool) string {
+	if !found {
+		return "not reached"
+	}
+	return state.String()
+}
+
+// AssertCovered check that all nodes, branches, and join outcomes of a coverage have happened.
+func AssertCovered(t testing.TB, coverage *hoff.Coverage) {
+	t.Helper()
+	if report := coverage.Report(); !report.IsComplete() {
+		t.Errorf("coverage - not complete:\n%v", report)
+	}
+}
@@ -0,0 +1,217 @@
+package hofftest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/rlespinasse/hoff"
+)
+
+// recorder is a testing.TB who record the errors of the assertions.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type money struct{ cents int }
+
+func testResult() (hoff.ComputationResult, *MockNode) {
+	check := NewMockDecision("check", false)
+	found := NewMockAction("found").Store("found", true)
+	fail := NewMockAction("fail", hoff.NewAbortComputeState(errors.New("missing")))
+	after := NewMockAction("after")
+
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(check)
+	ns.AddNode(found)
+	ns.AddNode(fail)
+	ns.AddNode(after)
+	ns.AddLinkOnBranch(check, found, true)
+	ns.AddLinkOnBranch(check, fail, false)
+	ns.AddLink(fail, after)
+	ns.Activate()
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	return eng.Compute(map[string]interface{}{"key": "value"}), check
+}
+
+func Test_Assertions(t *testing.T) {
+	result, _ := testResult()
+
+	testCases := []struct {
+		name           string
+		givenAssertion func(t testing.TB)
+		expectedErrors []string
+	}{
+		{
+			name:           "Assert the path",
+			givenAssertion: func(t testing.TB) { AssertPath(t, result, "check", "fail") },
+		},
+		{
+			name:           "Assert a wrong path",
+			givenAssertion: func(t testing.TB) { AssertPath(t, result, "check", "found") },
+			expectedErrors: []string{"path - got: [check fail], want: [check found]"},
+		},
+		{
+			name:           "Assert the skipped nodes",
+			givenAssertion: func(t testing.TB) { AssertSkipped(t, result, "found") },
+		},
+		{
+			name:           "Assert wrong skipped nodes",
+			givenAssertion: func(t testing.TB) { AssertSkipped(t, result, "check", "after") },
+			expectedErrors: []string{
+				"state of check - got: 'Continue on false', want: 'Skip'",
+				"state of after - got: not reached, want: 'Skip'",
+			},
+		},
+		{
+			name:           "Assert the not reached nodes",
+			givenAssertion: func(t testing.TB) { AssertNotReached(t, result, "after") },
+		},
+		{
+			name:           "Assert the branch",
+			givenAssertion: func(t testing.TB) { AssertBranch(t, result, "check", false) },
+		},
+		{
+			name:           "Assert a wrong branch",
+			givenAssertion: func(t testing.TB) { AssertBranch(t, result, "check", true) },
+			expectedErrors: []string{"state of check - got: 'Continue on false', want: 'Continue on true'"},
+		},
+		{
+			name:           "Assert the aborting node",
+			givenAssertion: func(t testing.TB) { AssertAbortedAt(t, result, "fail") },
+		},
+		{
+			name:           "Assert a wrong aborting node",
+			givenAssertion: func(t testing.TB) { AssertAbortedAt(t, result, "check") },
+			expectedErrors: []string{"state of check - got: 'Continue on false', want: Abort"},
+		},
+		{
+			name:           "Assert the context",
+			givenAssertion: func(t testing.TB) { AssertContextContains(t, result, "key", "value") },
+		},
+		{
+			name:           "Assert a missing context key",
+			givenAssertion: func(t testing.TB) { AssertContextContains(t, result, "found", true) },
+			expectedErrors: []string{"context - missing key: found"},
+		},
+		{
+			name:           "Assert a wrong context value",
+			givenAssertion: func(t testing.TB) { AssertContextContains(t, result, "key", "other") },
+			expectedErrors: []string{`context value of key - got: "value", want: "other"`},
+		},
+		{
+			name: "Assert a context value with unexported fields",
+			givenAssertion: func(t testing.TB) {
+				AssertContextContains(t, hoff.ComputationResult{Data: map[string]interface{}{"price": money{cents: 100}}}, "price", money{cents: 100})
+			},
+		},
+		{
+			name: "Assert a wrong context value with unexported fields",
+			givenAssertion: func(t testing.TB) {
+				AssertContextContains(t, hoff.ComputationResult{Data: map[string]interface{}{"price": money{cents: 100}}}, "price", money{cents: 200})
+			},
+			expectedErrors: []string{"context value of price - got: hofftest.money{cents:100}, want: hofftest.money{cents:200}"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			testCase.givenAssertion(r)
+
+			if !cmp.Equal(r.errors, testCase.expectedErrors) {
+				t.Errorf("errors - got: %q, want: %q", r.errors, testCase.expectedErrors)
+			}
+		})
+	}
+}
+
+func Test_AssertCovered(t *testing.T) {
+	check := NewMockDecision("check", true, false)
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(check)
+	ns.AddNode(NewMockAction("found"))
+	ns.AddNode(NewMockAction("missing"))
+	ns.AddLinkOnBranch(check, ns.Nodes()[1], true)
+	ns.AddLinkOnBranch(check, ns.Nodes()[2], false)
+	ns.Activate()
+
+	coverage, _ := hoff.NewCoverage(ns)
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureCoverage(coverage)
+
+	eng.Compute(map[string]interface{}{})
+	r := &recorder{TB: t}
+	AssertCovered(r, coverage)
+	expectedErrors := []string{"coverage - not complete:\ncomputations: 1\nnodes: 2/3 covered\nbranches: 1/2 covered\njoins: 0/0 covered\n- node never computed: missing\n- branch never taken: check on false\n"}
+	if !cmp.Equal(r.errors, expectedErrors) {
+		t.Errorf("errors - got: %q, want: %q", r.errors, expectedErrors)
+	}
+
+	eng.Compute(map[string]interface{}{})
+	AssertCovered(t, coverage)
+}
+
+func Test_MockNode(t *testing.T) {
+	result, check := testResult()
+
+	AssertContextContains(t, result, "key", "value")
+	if check.Calls() != 1 {
+		t.Errorf("calls - got: %v, want: %v", check.Calls(), 1)
+	}
+	expectedContexts := []map[string]interface{}{{"key": "value"}}
+	if !cmp.Equal(check.Contexts(), expectedContexts) {
+		t.Errorf("contexts - got: %v, want: %v", check.Contexts(), expectedContexts)
+	}
+
+	action := NewMockAction("action").Store("stored", true).ThenReturn(
+		hoff.NewContinueComputeState(),
+		hoff.NewAbortComputeState(errors.New("second call")),
+	)
+	states := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		context := hoff.NewContextWithoutData()
+		context.Store("call", i)
+		states = append(states, action.Compute(context).String())
+	}
+	expectedStates := []string{"'Continue'", "'Abort on second call'", "'Abort on second call'"}
+	if !cmp.Equal(states, expectedStates) {
+		t.Errorf("states - got: %v, want: %v", states, expectedStates)
+	}
+	last, _ := action.LastContext()
+	if !cmp.Equal(last, map[string]interface{}{"call": 2}) {
+		t.Errorf("last context - got: %v, want: %v", last, map[string]interface{}{"call": 2})
+	}
+}
+
+func Test_MockNode_ThenReturn_after_calls(t *testing.T) {
+	action := NewMockAction("action", hoff.NewAbortComputeState(errors.New("first script")))
+	action.Compute(hoff.NewContextWithoutData())
+	action.Compute(hoff.NewContextWithoutData())
+
+	action.ThenReturn(
+		hoff.NewContinueComputeState(),
+		hoff.NewAbortComputeState(errors.New("second script")),
+	)
+	states := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		states = append(states, action.Compute(hoff.NewContextWithoutData()).String())
+	}
+	expectedStates := []string{"'Continue'", "'Abort on second script'", "'Abort on second script'"}
+	if !cmp.Equal(states, expectedStates) {
+		t.Errorf("states - got: %v, want: %v", states, expectedStates)
+	}
+	if action.Calls() != 5 {
+		t.Errorf("calls - got: %v, want: %v", action.Calls(), 5)
+	}
+}
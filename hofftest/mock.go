@@ -0,0 +1,150 @@
+/*
+Package hofftest provide mock nodes, and assertions on computation results, to test hoff workflows.
+
+Build a node system with mock nodes, compute it, and assert on the result:
+
+	check := hofftest.NewMockDecision("check", true)
+	store := hofftest.NewMockAction("store").Store("stored", true)
+
+	ns := hoff.NewNodeSystem()
+	ns.AddNode(check)
+	ns.AddNode(store)
+	ns.AddLinkOnBranch(check, store, true)
+	ns.Activate()
+
+	eng := hoff.NewEngine(hoff.SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	result := eng.Compute(map[string]interface{}{})
+
+	hofftest.AssertPath(t, result, "check", "store")
+	hofftest.AssertContextContains(t, result, "stored", true)
+*/
+package hofftest
+
+import (
+	"sync"
+
+	"github.com/rlespinasse/hoff"
+)
+
+// MockNode is a scriptable node who record the contexts it see, and return canned compute states.
+type MockNode struct {
+	mutex    sync.Mutex
+	id       string
+	decide   bool
+	states   []hoff.ComputeState
+	next     int
+	stores   map[string]interface{}
+	contexts []map[string]interface{}
+}
+
+// NewMockAction create a mock action node who return the compute states in order (the last one being repeated),
+// or a continue state without compute states.
+func NewMockAction(id string, states ...hoff.ComputeState) *MockNode {
+	if len(states) == 0 {
+		states = []hoff.ComputeState{hoff.NewContinueComputeState()}
+	}
+	return &MockNode{
+		id:     id,
+		states: states,
+		stores: make(map[string]interface{}),
+	}
+}
+
+// NewMockDecision create a mock decision node who take the branches in order (the last one being repeated),
+// or the true branch without branches.
+func NewMockDecision(id string, branches ...bool) *MockNode {
+	if len(branches) == 0 {
+		branches = []bool{true}
+	}
+	states := make([]hoff.ComputeState, 0, len(branches))
+	for _, branch := range branches {
+		states = append(states, hoff.NewContinueOnBranchComputeState(branch))
+	}
+	return &MockNode{
+		id:     id,
+		decide: true,
+		states: states,
+		stores: make(map[string]interface{}),
+	}
+}
+
+// ThenReturn replace the compute states returned by the mock node, the next computation returning the first one.
+func (m *MockNode) ThenReturn(states ...hoff.ComputeState) *MockNode {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.states = append(make([]hoff.ComputeState, 0, len(states)), states...)
+	m.next = 0
+	return m
+}
+
+// Store add a value to store in the context when the mock node continue.
+func (m *MockNode) Store(key string, value interface{}) *MockNode {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.stores[key] = value
+	return m
+}
+
+// ID give the identifier of the mock node.
+func (m *MockNode) ID() string {
+	return m.id
+}
+
+func (m *MockNode) String() string {
+	return m.id
+}
+
+// Compute record a copy of the context data, and return the next canned compute state.
+func (m *MockNode) Compute(c *hoff.Context) hoff.ComputeState {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	seen := make(map[string]interface{}, len(c.Data))
+	for key, value := range c.Data {
+		seen[key] = value
+	}
+	m.contexts = append(m.contexts, seen)
+
+	state := hoff.NewContinueComputeState()
+	if len(m.states) > 0 {
+		state = m.states[m.next]
+		if m.next < len(m.states)-1 {
+			m.next++
+		}
+	}
+	if state.Value == hoff.ContinueState {
+		for key, value := range m.stores {
+			c.Store(key, value)
+		}
+	}
+	return state
+}
+
+// DecideCapability tell if the mock node is a decision node.
+func (m *MockNode) DecideCapability() bool {
+	return m.decide
+}
+
+// Calls give the number of computations of the mock node.
+func (m *MockNode) Calls() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.contexts)
+}
+
+// Contexts give a copy of the context data seen by each computation of the mock node.
+func (m *MockNode) Contexts() []map[string]interface{} {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append(make([]map[string]interface{}, 0, len(m.contexts)), m.contexts...)
+}
+
+// LastContext give a copy of the context data seen by the last computation of the mock node.
+func (m *MockNode) LastContext() (map[string]interface{}, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if len(m.contexts) == 0 {
+		return nil, false
+	}
+	return m.contexts[len(m.contexts)-1], true
+}
@@ -0,0 +1,310 @@
+package hoff
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultJobRetention is the default duration to keep a finished job in the job store of an engine.
+const DefaultJobRetention = time.Hour
+
+var (
+	// ErrJobNotFound is the error of a job who is not (or no more) in a job store.
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// JobStatus is the status of a computation submitted to an engine.
+type JobStatus string
+
+const (
+	// JobPending tell that the job computation is not started.
+	JobPending JobStatus = "pending"
+	// JobRunning tell that the job computation is running.
+	JobRunning = "running"
+	// JobDone tell that the job computation is finished, with or without error.
+	JobDone = "done"
+	// JobCancelled tell that the job computation have been cancelled.
+	JobCancelled = "cancelled"
+	// JobDropped tell that the job have been rejected, or dropped, by the full queue of the engine before its computation.
+	JobDropped = "dropped"
+)
+
+// Job hold the status, and the result (once finished), of a computation submitted to an engine.
+type Job struct {
+	ID          string
+	Priority    int
+	Status      JobStatus
+	SubmittedAt time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Result      ComputationResult
+}
+
+// IsFinished tell if the job computation is done, cancelled, or dropped.
+func (j Job) IsFinished() bool {
+	return j.Status == JobDone || j.Status == JobCancelled || j.Status == JobDropped
+}
+
+// JobStore keep the jobs of an engine.
+type JobStore interface {
+	// Save create, or update, a job
+	Save(job Job) error
+	// Load get a job by its identifier, or ErrJobNotFound
+	Load(id string) (Job, error)
+	// Expire delete the finished jobs who are finished before a time
+	Expire(before time.Time) error
+}
+
+// MemoryJobStore is a JobStore who keep the jobs in memory.
+type MemoryJobStore struct {
+	mutex sync.RWMutex
+	jobs  map[string]Job
+}
+
+// NewMemoryJobStore create an empty in-memory job store.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs: make(map[string]Job),
+	}
+}
+
+// Save create, or update, a job in memory.
+func (s *MemoryJobStore) Save(job Job) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+// Load get a job from memory.
+func (s *MemoryJobStore) Load(id string) (Job, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	job, found := s.jobs[id]
+	if !found {
+		return Job{}, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Expire delete the finished jobs from memory who are finished before a time.
+func (s *MemoryJobStore) Expire(before time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for id, job := range s.jobs {
+		if job.IsFinished() && job.FinishedAt.Before(before) {
+			delete(s.jobs, id)
+		}
+	}
+	return nil
+}
+
+// ConfigureJobStore replace the in-memory job store of the engine (before any submitted job).
+// A job whose save fail twice is kept in memory by the engine until saved, or expired.
+func (e *Engine) ConfigureJobStore(store JobStore) error {
+	if store == nil {
+		return errors.New("job store need to be defined")
+	}
+	e.jobsMutex.Lock()
+	defer e.jobsMutex.Unlock()
+	if len(e.jobCancels) > 0 {
+		return errors.New("can't configure job store with running jobs")
+	}
+	e.jobs = store
+	return nil
+}
+
+// ConfigureJobRetention set the duration to keep a finished job in the job store.
+func (e *Engine) ConfigureJobRetention(retention time.Duration) {
+	e.jobsMutex.Lock()
+	defer e.jobsMutex.Unlock()
+	e.jobRetention = retention
+}
+
+// Submit run a computation in background against node system with input data,
+// and return the identifier of its job to follow it.
+func (e *Engine) Submit(data map[string]interface{}) (string, error) {
+	return e.SubmitWithPriority(context.Background(), data, 0)
+}
+
+// SubmitWithPriority run a computation in background like Submit, with a priority.
+// The computation run on a deep copy of the input data, the caller keeping the ownership of its data.
+// When the engine have a queue, the jobs of higher priority are computed first,
+// and a job rejected by the full queue give ErrQueueFull with the identifier of the dropped job.
+// The context only stop the wait for a free place in the full queue with ShedBlock policy, not the computation.
+func (e *Engine) SubmitWithPriority(ctx context.Context, data map[string]interface{}, priority int) (string, error) {
+	id, err := newJobID()
+	if err != nil {
+		return "", err
+	}
+
+	e.jobsMutex.Lock()
+	now := time.Now()
+	if err := e.jobs.Expire(now.Add(-e.jobRetention)); err != nil {
+		e.jobsMutex.Unlock()
+		return "", err
+	}
+	for unsavedID, unsaved := range e.unsavedJobs {
+		if unsaved.IsFinished() && unsaved.FinishedAt.Before(now.Add(-e.jobRetention)) {
+			delete(e.unsavedJobs, unsavedID)
+		}
+	}
+	job := Job{
+		ID:          id,
+		Priority:    priority,
+		Status:      JobPending,
+		SubmittedAt: now,
+	}
+	if err := e.jobs.Save(job); err != nil {
+		e.jobsMutex.Unlock()
+		return "", err
+	}
+
+	jobCtx, cancel := context.WithCancel(context.Background())
+	e.jobCancels[id] = cancel
+	queue := e.queue
+	e.jobsMutex.Unlock()
+	data = deepCopyData(data)
+	if queue == nil {
+		go e.runJob(jobCtx, job, data)
+		return id, nil
+	}
+
+	dropped, err := queue.push(ctx, queuedJob{job: job, ctx: jobCtx, data: data})
+	if dropped != nil {
+		e.dropJob(dropped.job, ErrJobDropped)
+	}
+	if err != nil {
+		e.dropJob(job, err)
+		return id, err
+	}
+	return id, nil
+}
+
+// Status get the status of a submitted job.
+func (e *Engine) Status(id string) (JobStatus, error) {
+	e.jobsMutex.Lock()
+	job, err := e.loadJob(id)
+	e.jobsMutex.Unlock()
+	if err != nil {
+		return "", err
+	}
+	return job.Status, nil
+}
+
+// Result get the computation result of a finished job.
+func (e *Engine) Result(id string) (ComputationResult, error) {
+	e.jobsMutex.Lock()
+	job, err := e.loadJob(id)
+	e.jobsMutex.Unlock()
+	if err != nil {
+		return ComputationResult{}, err
+	}
+	if !job.IsFinished() {
+		return ComputationResult{}, fmt.Errorf("can't get result of %v job: %v", job.Status, id)
+	}
+	return job.Result, nil
+}
+
+// Cancel stop a pending, or running, job before computing its next node.
+// A job waiting in the queue of the engine is removed from it, and cancelled right away.
+func (e *Engine) Cancel(id string) error {
+	e.jobsMutex.Lock()
+	defer e.jobsMutex.Unlock()
+	cancel, found := e.jobCancels[id]
+	if !found {
+		job, err := e.loadJob(id)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("can't cancel %v job: %v", job.Status, id)
+	}
+	if e.queue != nil {
+		if entry, removed := e.queue.remove(id); removed {
+			e.cancelQueuedJob(entry.job, context.Canceled)
+			return nil
+		}
+	}
+	cancel()
+	return nil
+}
+
+func (e *Engine) runJob(ctx context.Context, job Job, data map[string]interface{}) {
+	e.jobsMutex.Lock()
+	if ctx.Err() != nil {
+		// cancelled before its computation, the job is never running
+		defer e.jobsMutex.Unlock()
+		job.Status = JobCancelled
+		job.Result = ComputationResult{Error: ctx.Err()}
+		e.finishJob(job)
+		return
+	}
+	job.Status = JobRunning
+	job.StartedAt = time.Now()
+	e.saveJob(job)
+	e.jobsMutex.Unlock()
+
+	job.Result = e.ComputeContext(ctx, data)
+
+	e.jobsMutex.Lock()
+	defer e.jobsMutex.Unlock()
+	job.Status = JobDone
+	if ctx.Err() != nil && job.Result.Error == ctx.Err() {
+		job.Status = JobCancelled
+	}
+	e.finishJob(job)
+}
+
+// dropJob finish a job who will never be computed.
+func (e *Engine) dropJob(job Job, err error) {
+	e.jobsMutex.Lock()
+	defer e.jobsMutex.Unlock()
+	job.Status = JobDropped
+	job.Result = ComputationResult{Error: err}
+	e.finishJob(job)
+}
+
+// finishJob save a finished job, and release its cancellation (under the jobs mutex).
+func (e *Engine) finishJob(job Job) {
+	job.FinishedAt = time.Now()
+	e.saveJob(job)
+	if cancel, found := e.jobCancels[job.ID]; found {
+		cancel()
+		delete(e.jobCancels, job.ID)
+	}
+}
+
+// saveJob save a job in the job store, retrying once, and keep it in memory while the job store fail to save it,
+// for a job to never stay pending, or running, once finished (under the jobs mutex).
+func (e *Engine) saveJob(job Job) {
+	err := e.jobs.Save(job)
+	if err != nil {
+		err = e.jobs.Save(job)
+	}
+	if err != nil {
+		e.unsavedJobs[job.ID] = job
+		return
+	}
+	delete(e.unsavedJobs, job.ID)
+}
+
+// loadJob get a job kept in memory due to a failed save, or from the job store (under the jobs mutex).
+func (e *Engine) loadJob(id string) (Job, error) {
+	if job, found := e.unsavedJobs[id]; found {
+		return job, nil
+	}
+	return e.jobs.Load(id)
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
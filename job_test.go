@@ -0,0 +1,257 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_Submit(t *testing.T) {
+	release := make(chan struct{})
+	waitAction, _ := NewActionNode("waitAction", func(c *Context) error {
+		<-release
+		c.Store("waited", true)
+		return nil
+	})
+	storeAction, _ := NewActionNode("storeAction", func(c *Context) error {
+		c.Store("stored", true)
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(waitAction)
+	ns.AddNode(storeAction)
+	ns.AddLink(waitAction, storeAction)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	id, err := eng.Submit(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("submit error - got: %+v", err)
+	}
+	waitForJobStatus(t, eng, id, JobRunning)
+
+	_, err = eng.Result(id)
+	expectedError := fmt.Errorf("can't get result of running job: %v", id)
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("result error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	close(release)
+	waitForJobStatus(t, eng, id, JobDone)
+
+	result, err := eng.Result(id)
+	expectedResult := ComputationResult{
+		Data: map[string]interface{}{"waited": true, "stored": true},
+		Report: map[Node]ComputeState{
+			waitAction:  NewContinueComputeState(),
+			storeAction: NewContinueComputeState(),
+		},
+		Path:    []Node{waitAction, storeAction},
+		Version: 1,
+	}
+	if err != nil || !cmp.Equal(result, expectedResult, NodeComparator, errorComparator) {
+		t.Errorf("result - got: %+v (%+v), want: %+v", result, err, expectedResult)
+	}
+
+	err = eng.Cancel(id)
+	expectedError = fmt.Errorf("can't cancel done job: %v", id)
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("cancel error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Engine_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	waitAction, _ := NewActionNode("waitAction", func(c *Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	storeAction, _ := NewActionNode("storeAction", func(c *Context) error {
+		c.Store("stored", true)
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(waitAction)
+	ns.AddNode(storeAction)
+	ns.AddLink(waitAction, storeAction)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	id, _ := eng.Submit(map[string]interface{}{})
+	<-started
+	if err := eng.Cancel(id); err != nil {
+		t.Fatalf("cancel error - got: %+v", err)
+	}
+	close(release)
+	waitForJobStatus(t, eng, id, JobCancelled)
+
+	result, _ := eng.Result(id)
+	expectedResult := ComputationResult{
+		Data:  map[string]interface{}{},
+		Error: context.Canceled,
+		Report: map[Node]ComputeState{
+			waitAction: NewContinueComputeState(),
+		},
+		Path:    []Node{waitAction},
+		Version: 1,
+	}
+	if !cmp.Equal(result, expectedResult, NodeComparator, errorComparator) {
+		t.Errorf("result - got: %+v, want: %+v", result, expectedResult)
+	}
+}
+
+func Test_Engine_runJob_already_cancelled(t *testing.T) {
+	computed := false
+	storeAction, _ := NewActionNode("storeAction", func(c *Context) error {
+		computed = true
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(storeAction)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	job := Job{ID: "cancelled", Status: JobPending, SubmittedAt: time.Now()}
+	eng.jobs.Save(job)
+	eng.runJob(ctx, job, map[string]interface{}{})
+
+	stored, _ := eng.jobs.Load(job.ID)
+	if stored.Status != JobCancelled || !stored.StartedAt.IsZero() || computed {
+		t.Errorf("job - got: %+v (computed: %v), want: cancelled without start", stored, computed)
+	}
+	expectedResult := ComputationResult{Error: context.Canceled}
+	if !cmp.Equal(stored.Result, expectedResult, NodeComparator, errorComparator) {
+		t.Errorf("result - got: %+v, want: %+v", stored.Result, expectedResult)
+	}
+}
+
+func Test_Engine_job_retention(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureJobRetention(0)
+
+	firstID, _ := eng.Submit(map[string]interface{}{})
+	waitForJobStatus(t, eng, firstID, JobDone)
+	secondID, _ := eng.Submit(map[string]interface{}{})
+
+	if _, err := eng.Status(firstID); err != ErrJobNotFound {
+		t.Errorf("expired job status - got: %+v, want: %+v", err, ErrJobNotFound)
+	}
+	waitForJobStatus(t, eng, secondID, JobDone)
+}
+
+func Test_Engine_ConfigureJobStore(t *testing.T) {
+	store := NewMemoryJobStore()
+	store.Save(Job{ID: "stored", Status: JobDone, FinishedAt: time.Now()})
+
+	eng := NewEngine(SequentialComputation)
+	if err := eng.ConfigureJobStore(store); err != nil {
+		t.Fatalf("error - got: %+v", err)
+	}
+
+	status, err := eng.Status("stored")
+	if err != nil || status != JobDone {
+		t.Errorf("status - got: %+v (%+v), want: %+v", status, err, JobDone)
+	}
+	if _, err := eng.Status("unknown"); err != ErrJobNotFound {
+		t.Errorf("unknown job error - got: %+v, want: %+v", err, ErrJobNotFound)
+	}
+}
+
+func waitForJobStatus(t *testing.T, eng *Engine, id string, expectedStatus JobStatus) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		status, err := eng.Status(id)
+		if err == nil && status == expectedStatus {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job status - got: %+v (%+v), want: %+v", status, err, expectedStatus)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// failingJobStore is a memory job store who fail to save the started jobs.
+type failingJobStore struct {
+	*MemoryJobStore
+}
+
+func (s failingJobStore) Save(job Job) error {
+	if job.Status != JobPending {
+		return errors.New("store unavailable")
+	}
+	return s.MemoryJobStore.Save(job)
+}
+
+func Test_Engine_Submit_with_failing_job_store(t *testing.T) {
+	storeAction, _ := NewActionNode("storeAction", func(c *Context) error {
+		c.Store("stored", true)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(storeAction)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureJobStore(failingJobStore{NewMemoryJobStore()})
+
+	id, _ := eng.Submit(map[string]interface{}{})
+	waitForJobStatus(t, eng, id, JobDone)
+
+	result, err := eng.Result(id)
+	if err != nil || !cmp.Equal(result.Data, map[string]interface{}{"stored": true}) {
+		t.Errorf("result - got: %+v (%+v)", result, err)
+	}
+}
+
+func Test_Engine_Submit_copy_data(t *testing.T) {
+	tagAction, _ := NewActionNode("tagAction", func(c *Context) error {
+		order, _ := c.Read("order")
+		order.(map[string]interface{})["tag"] = "tagged"
+		c.Store("tagged", true)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(tagAction)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	data := map[string]interface{}{"order": map[string]interface{}{}}
+	id, _ := eng.Submit(data)
+	waitForJobStatus(t, eng, id, JobDone)
+
+	expectedData := map[string]interface{}{"order": map[string]interface{}{}}
+	if !cmp.Equal(data, expectedData) {
+		t.Errorf("submitted data - got: %+v, want: %+v", data, expectedData)
+	}
+	result, _ := eng.Result(id)
+	expectedResultData := map[string]interface{}{"order": map[string]interface{}{"tag": "tagged"}, "tagged": true}
+	if !cmp.Equal(result.Data, expectedResultData) {
+		t.Errorf("result data - got: %+v, want: %+v", result.Data, expectedResultData)
+	}
+}
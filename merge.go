@@ -0,0 +1,173 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MergePort define a link to create, during a merge, from a node of the system
+// to a node of the merged system. Both nodes are found by their identifiers.
+type MergePort struct {
+	From   string
+	To     string
+	Branch *bool
+}
+
+// NewMergePort create a merge port from a node identifier to another node identifier.
+func NewMergePort(from, to string) MergePort {
+	return MergePort{From: from, To: to}
+}
+
+// NewMergePortOnBranch create a merge port from a node identifier (on a specific branch) to another node identifier.
+func NewMergePortOnBranch(from, to string, branch bool) MergePort {
+	return MergePort{From: from, To: to, Branch: boolPointer(branch)}
+}
+
+// NodeIDCollisionError is raised when merging two node systems
+// who have a node with the same identifier.
+type NodeIDCollisionError struct {
+	ID    string
+	Node  Node
+	Other Node
+}
+
+func (e NodeIDCollisionError) Error() string {
+	return fmt.Sprintf("can't merge node systems with the same node id: %v", e.ID)
+}
+
+// Merge add the nodes, join modes, and links of another node system into the system before activation.
+// The ports create links from nodes of the system to nodes of the merged system.
+// Nothing is merged if a node identifier is used in both systems, or if a port can't be linked.
+func (s *NodeSystem) Merge(o *NodeSystem, ports ...MergePort) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't merge node system, node system is freeze due to activation")
+	}
+	if o == nil {
+		return false, errors.New("can't merge a missing node system")
+	}
+
+	mergedNodesByID := make(map[string]Node)
+	for _, node := range o.nodes {
+		if other, found := s.nodesByID[node.ID()]; found {
+			return false, NodeIDCollisionError{ID: node.ID(), Node: other, Other: node}
+		}
+		mergedNodesByID[node.ID()] = node
+	}
+
+	links := make([]NodeLink, 0, len(ports))
+	for _, port := range ports {
+		from, foundFrom := s.nodesByID[port.From]
+		if !foundFrom {
+			return false, fmt.Errorf("can't have unknown node '%v' as 'from' in merge port", port.From)
+		}
+		to, foundTo := mergedNodesByID[port.To]
+		if !foundTo {
+			return false, fmt.Errorf("can't have unknown node '%v' as 'to' in merge port", port.To)
+		}
+		links = append(links, NodeLink{From: from, To: to, Branch: port.Branch})
+	}
+
+	merged := s.Clone()
+	merged.nodes = append(merged.nodes, o.nodes...)
+	for _, node := range o.nodes {
+		merged.nodesByID[node.ID()] = node
+	}
+	merged.links = append(merged.links, o.links...)
+	for node, mode := range o.nodesJoinModes {
+		merged.nodesJoinModes[node] = mode
+	}
+	for node, limiter := range o.nodesRateLimits {
+		merged.nodesRateLimits[node] = limiter
+	}
+	for node, bulkhead := range o.nodesBulkheads {
+		merged.nodesBulkheads[node] = bulkhead
+	}
+	for node, breaker := range o.nodesCircuitBreakers {
+		merged.nodesCircuitBreakers[node] = breaker
+	}
+	for _, link := range links {
+		_, err := merged.addLink(link.From, link.To, link.Branch)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	s.nodes = merged.nodes
+	s.nodesByID = merged.nodesByID
+	s.links = merged.links
+	s.nodesJoinModes = merged.nodesJoinModes
+	s.nodesRateLimits = merged.nodesRateLimits
+	s.nodesBulkheads = merged.nodesBulkheads
+	s.nodesCircuitBreakers = merged.nodesCircuitBreakers
+	return true, nil
+}
+
+// Chain merge another node system into the system before activation
+// by linking each terminal node of the system to each initial node of the merged system.
+// The join mode is configured on the initial nodes of the merged system
+// when they are linked to multiple terminal nodes.
+func (s *NodeSystem) Chain(o *NodeSystem, mode JoinMode) (bool, error) {
+	if o == nil {
+		return false, errors.New("can't chain a missing node system")
+	}
+
+	terminalNodes := s.terminalNodes()
+	for _, node := range terminalNodes {
+		if node.DecideCapability() {
+			return false, fmt.Errorf("can't chain from decision node without link from it: %+v", node)
+		}
+	}
+	if len(terminalNodes) > 1 && mode == JoinNone {
+		return false, fmt.Errorf("can't chain multiple terminal nodes (%v) without join mode", len(terminalNodes))
+	}
+
+	ports := make([]MergePort, 0)
+	for _, to := range o.headNodes() {
+		for _, from := range terminalNodes {
+			ports = append(ports, NewMergePort(from.ID(), to.ID()))
+		}
+	}
+	ok, err := s.Merge(o, ports...)
+	if err != nil {
+		return ok, err
+	}
+
+	if len(terminalNodes) > 1 {
+		for _, node := range o.headNodes() {
+			if s.JoinModeOfNode(node) == JoinNone {
+				s.nodesJoinModes[node] = mode
+			}
+		}
+	}
+	return true, nil
+}
+
+// terminalNodes get the nodes without link from them.
+func (s *NodeSystem) terminalNodes() []Node {
+	fromNodes := make(map[Node]bool)
+	for _, link := range s.links {
+		fromNodes[link.From] = true
+	}
+	nodes := make([]Node, 0)
+	for _, node := range s.nodes {
+		if !fromNodes[node] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// headNodes get the nodes without link to them, activated or not.
+func (s *NodeSystem) headNodes() []Node {
+	toNodes := make(map[Node]bool)
+	for _, link := range s.links {
+		toNodes[link.To] = true
+	}
+	nodes := make([]Node, 0)
+	for _, node := range s.nodes {
+		if !toNodes[node] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,148 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_Merge(t *testing.T) {
+	a1, _ := NewActionNode("a1", func(*Context) error { return nil })
+	d2, _ := NewDecisionNode("d2", func(*Context) (bool, error) { return true, nil })
+	a3, _ := NewActionNode("a3", func(*Context) error { return nil })
+	b1, _ := NewActionNode("b1", func(*Context) error { return nil })
+	b2, _ := NewActionNode("b2", func(*Context) error { return nil })
+	otherA1, _ := NewActionNode("a1", func(*Context) error { return nil })
+
+	fragment := func(nodes []Node, modes map[Node]JoinMode, links []NodeLink) *NodeSystem {
+		system := NewNodeSystem()
+		loadNodeSystem(system, nodes, modes, links)
+		return system
+	}
+
+	testCases := []struct {
+		name               string
+		givenSystem        *NodeSystem
+		givenMergedSystem  *NodeSystem
+		givenPorts         []MergePort
+		expectedNodeSystem *NodeSystem
+		expectedError      error
+	}{
+		{
+			name:              "Can merge without port",
+			givenSystem:       fragment([]Node{a1}, nil, nil),
+			givenMergedSystem: fragment([]Node{b1, b2}, nil, []NodeLink{newNodeLink(b1, b2)}),
+			expectedNodeSystem: &NodeSystem{
+				nodes:          []Node{a1, b1, b2},
+				nodesJoinModes: map[Node]JoinMode{},
+				links:          []NodeLink{newNodeLink(b1, b2)},
+			},
+		},
+		{
+			name:              "Can merge on ports",
+			givenSystem:       fragment([]Node{a1, d2}, nil, []NodeLink{newNodeLink(a1, d2)}),
+			givenMergedSystem: fragment([]Node{b1, b2}, map[Node]JoinMode{b2: JoinOr}, nil),
+			givenPorts: []MergePort{
+				NewMergePortOnBranch("d2", "b1", true),
+				NewMergePortOnBranch("d2", "b2", false),
+			},
+			expectedNodeSystem: &NodeSystem{
+				nodes:          []Node{a1, d2, b1, b2},
+				nodesJoinModes: map[Node]JoinMode{b2: JoinOr},
+				links: []NodeLink{
+					newNodeLink(a1, d2),
+					newNodeLinkOnBranch(d2, b1, true),
+					newNodeLinkOnBranch(d2, b2, false),
+				},
+			},
+		},
+		{
+			name:              "Can't merge with the same node id",
+			givenSystem:       fragment([]Node{a1, a3}, nil, nil),
+			givenMergedSystem: fragment([]Node{otherA1}, nil, nil),
+			expectedNodeSystem: &NodeSystem{
+				nodes:          []Node{a1, a3},
+				nodesJoinModes: map[Node]JoinMode{},
+				links:          []NodeLink{},
+			},
+			expectedError: NodeIDCollisionError{ID: "a1", Node: a1, Other: otherA1},
+		},
+		{
+			name:              "Can't merge on unknown port",
+			givenSystem:       fragment([]Node{a1}, nil, nil),
+			givenMergedSystem: fragment([]Node{b1}, nil, nil),
+			givenPorts:        []MergePort{NewMergePort("a1", "b2")},
+			expectedNodeSystem: &NodeSystem{
+				nodes:          []Node{a1},
+				nodesJoinModes: map[Node]JoinMode{},
+				links:          []NodeLink{},
+			},
+			expectedError: fmt.Errorf("can't have unknown node 'b2' as 'to' in merge port"),
+		},
+		{
+			name:              "Can't merge on port without needed branch",
+			givenSystem:       fragment([]Node{d2}, nil, nil),
+			givenMergedSystem: fragment([]Node{b1}, nil, nil),
+			givenPorts:        []MergePort{NewMergePort("d2", "b1")},
+			expectedNodeSystem: &NodeSystem{
+				nodes:          []Node{d2},
+				nodesJoinModes: map[Node]JoinMode{},
+				links:          []NodeLink{},
+			},
+			expectedError: fmt.Errorf("can't have missing branch"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := testCase.givenSystem.Merge(testCase.givenMergedSystem, testCase.givenPorts...)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(testCase.givenSystem, testCase.expectedNodeSystem) {
+				t.Errorf("system - got: %+v, want: %+v", testCase.givenSystem, testCase.expectedNodeSystem)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_Chain(t *testing.T) {
+	a1, _ := NewActionNode("a1", func(*Context) error { return nil })
+	a2, _ := NewActionNode("a2", func(*Context) error { return nil })
+	b1, _ := NewActionNode("b1", func(*Context) error { return nil })
+	b2, _ := NewActionNode("b2", func(*Context) error { return nil })
+
+	system := NewNodeSystem()
+	loadNodeSystem(system, []Node{a1, a2}, nil, nil)
+	mergedSystem := NewNodeSystem()
+	loadNodeSystem(mergedSystem, []Node{b1, b2}, nil, []NodeLink{newNodeLink(b1, b2)})
+
+	_, err := system.Chain(mergedSystem, JoinNone)
+	expectedError := errors.New("can't chain multiple terminal nodes (2) without join mode")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	_, err = system.Chain(mergedSystem, JoinAnd)
+	if err != nil {
+		t.Errorf("error - got: %+v", err)
+	}
+
+	expectedNodeSystem := &NodeSystem{
+		nodes:          []Node{a1, a2, b1, b2},
+		nodesJoinModes: map[Node]JoinMode{b1: JoinAnd},
+		links: []NodeLink{
+			newNodeLink(b1, b2),
+			newNodeLink(a1, b1),
+			newNodeLink(a2, b1),
+		},
+	}
+	if !cmp.Equal(system, expectedNodeSystem) {
+		t.Errorf("system - got: %+v, want: %+v", system, expectedNodeSystem)
+	}
+	if err := system.Activate(); err != nil {
+		t.Errorf("chained system must be valid, got: %+v", err)
+	}
+}
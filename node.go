@@ -6,6 +6,9 @@ import (
 
 // Node define
 type Node interface {
+	// ID give the identifier of the Node.
+	// It must be unique in a node system.
+	ID() string
 	// Compute a node based on a context
 	Compute(c *Context) ComputeState
 	// DecideCapability tell if the Node can decide.
@@ -14,8 +17,11 @@ type Node interface {
 }
 
 var (
-	// NodeComparator is a google/go-cmp comparator of Node
+	// NodeComparator is a google/go-cmp comparator of Node based on their identifiers
 	NodeComparator = cmp.Comparer(func(x, y Node) bool {
-		return x == y
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x == y || x.ID() == y.ID()
 	})
 )
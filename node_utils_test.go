@@ -13,6 +13,10 @@ func (n *SomeNode) Compute(c *Context) ComputeState {
 	return NewContinueComputeState()
 }
 
+func (n *SomeNode) ID() string {
+	return "SomeNode"
+}
+
 func (n *SomeNode) DecideCapability() bool {
 	return false
 }
@@ -24,6 +28,10 @@ func (n *AnotherNode) Compute(c *Context) ComputeState {
 	return NewContinueComputeState()
 }
 
+func (n *AnotherNode) ID() string {
+	return "AnotherNode"
+}
+
 func (n *AnotherNode) DecideCapability() bool {
 	return false
 }
@@ -0,0 +1,193 @@
+package hoff
+
+// nodeGraph is an indexed view of the nodes and links of a node system
+// to walk them using adjacency lists instead of scanning all links.
+type nodeGraph struct {
+	nodes    []Node
+	index    map[Node]int
+	declared map[Node]int
+	outLinks [][]NodeLink
+	inLinks  [][]NodeLink
+}
+
+// newNodeGraph index the declared nodes (in declaration order), then the
+// undeclared nodes only used by links, and link them by their adjacency.
+func newNodeGraph(s *NodeSystem) *nodeGraph {
+	g := &nodeGraph{
+		nodes:    make([]Node, 0, len(s.nodes)),
+		index:    make(map[Node]int, len(s.nodes)),
+		declared: make(map[Node]int, len(s.nodes)),
+	}
+	for _, node := range s.nodes {
+		g.declared[node]++
+		g.add(node)
+	}
+	for _, link := range s.links {
+		g.add(link.From)
+		g.add(link.To)
+	}
+
+	g.outLinks = make([][]NodeLink, len(g.nodes))
+	g.inLinks = make([][]NodeLink, len(g.nodes))
+	for _, link := range s.links {
+		if link.From == nil || link.To == nil {
+			continue
+		}
+		from, to := g.index[link.From], g.index[link.To]
+		g.outLinks[from] = append(g.outLinks[from], link)
+		g.inLinks[to] = append(g.inLinks[to], link)
+	}
+	return g
+}
+
+func (g *nodeGraph) add(n Node) {
+	if n == nil {
+		return
+	}
+	if _, found := g.index[n]; !found {
+		g.index[n] = len(g.nodes)
+		g.nodes = append(g.nodes, n)
+	}
+}
+
+func (g *nodeGraph) isDeclared(n Node) bool {
+	return g.declared[n] > 0
+}
+
+// stronglyConnectedComponents use the Tarjan's algorithm to give, for each node index,
+// the identifier of its strongly connected component.
+func (g *nodeGraph) stronglyConnectedComponents() []int {
+	count := len(g.nodes)
+	components := make([]int, count)
+	indexes := make([]int, count)
+	lowLinks := make([]int, count)
+	onStack := make([]bool, count)
+	stack := make([]int, 0, count)
+	for i := range indexes {
+		indexes[i] = -1
+	}
+
+	nextIndex := 0
+	nextComponent := 0
+	var strongConnect func(v int)
+	strongConnect = func(v int) {
+		indexes[v] = nextIndex
+		lowLinks[v] = nextIndex
+		nextIndex++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, link := range g.outLinks[v] {
+			w := g.index[link.To]
+			if indexes[w] == -1 {
+				strongConnect(w)
+				if lowLinks[w] < lowLinks[v] {
+					lowLinks[v] = lowLinks[w]
+				}
+			} else if onStack[w] && indexes[w] < lowLinks[v] {
+				lowLinks[v] = indexes[w]
+			}
+		}
+
+		if lowLinks[v] == indexes[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				components[w] = nextComponent
+				if w == v {
+					break
+				}
+			}
+			nextComponent++
+		}
+	}
+
+	for v := range g.nodes {
+		if indexes[v] == -1 {
+			strongConnect(v)
+		}
+	}
+	return components
+}
+
+// cycles give each distinct cycle of links only once.
+// Only the strongly connected components with more than one node are walked,
+// with the Johnson's algorithm, so a system without cycle is checked in linear time.
+// A cycle start from its first declared node, and follow the links in their declaration order.
+func (g *nodeGraph) cycles() [][]NodeLink {
+	components := g.stronglyConnectedComponents()
+	componentNodes := make(map[int][]int)
+	for v, component := range components {
+		componentNodes[component] = append(componentNodes[component], v)
+	}
+
+	cycles := make([][]NodeLink, 0)
+	blocked := make([]bool, len(g.nodes))
+	blockedBy := make([]map[int]bool, len(g.nodes))
+	path := make([]NodeLink, 0)
+
+	var unblock func(v int)
+	unblock = func(v int) {
+		blocked[v] = false
+		for w := range blockedBy[v] {
+			delete(blockedBy[v], w)
+			if blocked[w] {
+				unblock(w)
+			}
+		}
+	}
+
+	for start := range g.nodes {
+		component := components[start]
+		if len(componentNodes[component]) < 2 {
+			continue
+		}
+		inScope := func(v int) bool {
+			return v >= start && components[v] == component
+		}
+
+		var circuit func(v int) bool
+		circuit = func(v int) bool {
+			foundCycle := false
+			blocked[v] = true
+			for _, link := range g.outLinks[v] {
+				w := g.index[link.To]
+				if !inScope(w) {
+					continue
+				}
+				path = append(path, link)
+				if w == start {
+					cycle := make([]NodeLink, len(path))
+					copy(cycle, path)
+					cycles = append(cycles, cycle)
+					foundCycle = true
+				} else if !blocked[w] && circuit(w) {
+					foundCycle = true
+				}
+				path = path[:len(path)-1]
+			}
+			if foundCycle {
+				unblock(v)
+			} else {
+				for _, link := range g.outLinks[v] {
+					w := g.index[link.To]
+					if inScope(w) {
+						if blockedBy[w] == nil {
+							blockedBy[w] = make(map[int]bool)
+						}
+						blockedBy[w][v] = true
+					}
+				}
+			}
+			return foundCycle
+		}
+
+		for _, v := range componentNodes[component] {
+			blocked[v] = false
+			blockedBy[v] = nil
+		}
+		circuit(start)
+	}
+	return cycles
+}
@@ -0,0 +1,115 @@
+package hoff
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_nodeGraph_cycles(t *testing.T) {
+	a1, _ := NewActionNode("a1", func(*Context) error { return nil })
+	a2, _ := NewActionNode("a2", func(*Context) error { return nil })
+	a3, _ := NewActionNode("a3", func(*Context) error { return nil })
+	a4, _ := NewActionNode("a4", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name           string
+		givenNodes     []Node
+		givenLinks     []NodeLink
+		expectedCycles [][]NodeLink
+	}{
+		{
+			name:       "Can have no cycle in a chain",
+			givenNodes: []Node{a1, a2, a3},
+			givenLinks: []NodeLink{
+				newNodeLink(a1, a2),
+				newNodeLink(a2, a3),
+			},
+			expectedCycles: [][]NodeLink{},
+		},
+		{
+			name:       "Can find all cycles sharing the same node",
+			givenNodes: []Node{a1, a2, a3},
+			givenLinks: []NodeLink{
+				newNodeLink(a1, a2),
+				newNodeLink(a2, a1),
+				newNodeLink(a2, a3),
+				newNodeLink(a3, a2),
+			},
+			expectedCycles: [][]NodeLink{
+				{newNodeLink(a1, a2), newNodeLink(a2, a1)},
+				{newNodeLink(a2, a3), newNodeLink(a3, a2)},
+			},
+		},
+		{
+			name:       "Can find inner and outer cycles of the same nodes",
+			givenNodes: []Node{a1, a2, a3, a4},
+			givenLinks: []NodeLink{
+				newNodeLink(a1, a2),
+				newNodeLink(a2, a3),
+				newNodeLink(a3, a1),
+				newNodeLink(a3, a4),
+				newNodeLink(a4, a1),
+			},
+			expectedCycles: [][]NodeLink{
+				{newNodeLink(a1, a2), newNodeLink(a2, a3), newNodeLink(a3, a1)},
+				{newNodeLink(a1, a2), newNodeLink(a2, a3), newNodeLink(a3, a4), newNodeLink(a4, a1)},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			system := NewNodeSystem()
+			loadNodeSystem(system, testCase.givenNodes, nil, testCase.givenLinks)
+
+			cycles := newNodeGraph(system).cycles()
+			if !cmp.Equal(cycles, testCase.expectedCycles, nodeLinkComparator) {
+				t.Errorf("got: %+v, want: %+v", cycles, testCase.expectedCycles)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_IsValid_multiple_instances_count(t *testing.T) {
+	system := NewNodeSystem()
+	system.AddNode(someActionNode)
+	system.AddNode(someActionNode)
+	system.AddNode(someActionNode)
+
+	_, errs := system.IsValid()
+	expectedErrors := []error{
+		MultipleInstancesError{Node: someActionNode, Count: 3},
+	}
+	if !cmp.Equal(errs, expectedErrors, errorComparator) {
+		t.Errorf("got: %+v, want: %+v", errs, expectedErrors)
+	}
+}
+
+func Benchmark_NodeSystem_Activate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		system := generatedNodeSystem(500)
+		if err := system.Activate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// generatedNodeSystem create a layered system where each node is linked
+// to the two nodes of the following layer.
+func generatedNodeSystem(size int) *NodeSystem {
+	nodes := make([]Node, size)
+	system := NewNodeSystem()
+	for i := range nodes {
+		nodes[i], _ = NewActionNode(fmt.Sprintf("node%v", i), func(*Context) error { return nil })
+		system.AddNode(nodes[i])
+		system.ConfigureJoinModeOnNode(nodes[i], JoinOr)
+	}
+	for i := 0; i+3 < size; i += 2 {
+		system.AddLink(nodes[i], nodes[i+2])
+		system.AddLink(nodes[i], nodes[i+3])
+		system.AddLink(nodes[i+1], nodes[i+2])
+		system.AddLink(nodes[i+1], nodes[i+3])
+	}
+	return system
+}
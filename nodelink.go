@@ -8,29 +8,29 @@ import (
 
 var (
 	// nodeLinkComparator is a google/go-cmp comparator of Node Links
-	nodeLinkComparator = cmp.Comparer(func(x, y nodeLink) bool {
+	nodeLinkComparator = cmp.Comparer(func(x, y NodeLink) bool {
 		return cmp.Equal(x.From, y.From, NodeComparator) && cmp.Equal(x.To, y.To, NodeComparator) && cmp.Equal(x.Branch, y.Branch)
 	})
 )
 
-// nodeLink store all information needed to represent a link in the node system
-type nodeLink struct {
+// NodeLink store all information needed to represent a link in the node system
+type NodeLink struct {
 	From   Node
 	To     Node
 	Branch *bool
 }
 
 // newNodeLink create a new link from a node to another node
-func newNodeLink(from, to Node) nodeLink {
-	return nodeLink{
+func newNodeLink(from, to Node) NodeLink {
+	return NodeLink{
 		From: from,
 		To:   to,
 	}
 }
 
 // newNodeLinkOnBranch create a new link from a node (and his branch output) to another node
-func newNodeLinkOnBranch(from, to Node, branch bool) nodeLink {
-	return nodeLink{
+func newNodeLinkOnBranch(from, to Node, branch bool) NodeLink {
+	return NodeLink{
 		From:   from,
 		To:     to,
 		Branch: boolPointer(branch),
@@ -38,7 +38,7 @@ func newNodeLinkOnBranch(from, to Node, branch bool) nodeLink {
 }
 
 // String print human-readable version of a node link
-func (n nodeLink) String() string {
+func (n NodeLink) String() string {
 	branch := ""
 	if n.Branch != nil {
 		branch = fmt.Sprintf(" branch:%v", *n.Branch)
@@ -11,10 +11,15 @@ import (
 // The nodes are linked between them by link and join mode options.
 // An activated Node system will be walked throw Follow and Ancestors functions
 type NodeSystem struct {
-	activated      bool
-	nodes          []Node
-	nodesJoinModes map[Node]JoinMode
-	links          []nodeLink
+	activated            bool
+	version              string
+	nodes                []Node
+	nodesByID            map[string]Node
+	nodesJoinModes       map[Node]JoinMode
+	nodesRateLimits      map[Node]*rateLimiter
+	nodesBulkheads       map[Node]*bulkhead
+	nodesCircuitBreakers map[Node]*breaker
+	links                []NodeLink
 
 	initialNodes       []Node
 	followingNodesTree map[Node]map[*bool][]Node
@@ -25,13 +30,17 @@ type NodeSystem struct {
 // who need to be valid and activated in order to be used.
 func NewNodeSystem() *NodeSystem {
 	return &NodeSystem{
-		activated:          false,
-		nodes:              make([]Node, 0),
-		links:              make([]nodeLink, 0),
-		nodesJoinModes:     make(map[Node]JoinMode),
-		initialNodes:       make([]Node, 0),
-		followingNodesTree: make(map[Node]map[*bool][]Node),
-		ancestorsNodesTree: make(map[Node]map[*bool][]Node),
+		activated:            false,
+		nodes:                make([]Node, 0),
+		nodesByID:            make(map[string]Node),
+		links:                make([]NodeLink, 0),
+		nodesJoinModes:       make(map[Node]JoinMode),
+		nodesRateLimits:      make(map[Node]*rateLimiter),
+		nodesBulkheads:       make(map[Node]*bulkhead),
+		nodesCircuitBreakers: make(map[Node]*breaker),
+		initialNodes:         make([]Node, 0),
+		followingNodesTree:   make(map[Node]map[*bool][]Node),
+		ancestorsNodesTree:   make(map[Node]map[*bool][]Node),
 	}
 }
 
@@ -41,11 +50,22 @@ func (s *NodeSystem) Equal(o *NodeSystem) bool {
 }
 
 // AddNode add a node to the system before activation.
+// The node must have an identifier who is not used by another node of the system.
 func (s *NodeSystem) AddNode(n Node) (bool, error) {
 	if s.activated {
 		return false, errors.New("can't add node, node system is freeze due to activation")
 	}
+	if n == nil {
+		return false, errors.New("can't add a missing node")
+	}
+	if n.ID() == "" {
+		return false, fmt.Errorf("can't add node without id: %+v", n)
+	}
+	if other, found := s.nodesByID[n.ID()]; found && other != n {
+		return false, fmt.Errorf("can't add node, id '%v' is already used by another node", n.ID())
+	}
 	s.nodes = append(s.nodes, n)
+	s.nodesByID[n.ID()] = n
 	return true, nil
 }
 
@@ -68,18 +88,123 @@ func (s *NodeSystem) AddLinkOnBranch(from, to Node, branch bool) (bool, error) {
 	return s.addLink(from, to, &branch)
 }
 
+// RemoveNode remove a node from the system before activation,
+// with its join mode, its limits, and all the links from or to it.
+func (s *NodeSystem) RemoveNode(n Node) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't remove node, node system is freeze due to activation")
+	}
+	if !s.haveNode(n) {
+		return false, fmt.Errorf("can't remove undeclared node: %+v", n)
+	}
+
+	nodes := make([]Node, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		if node != n {
+			nodes = append(nodes, node)
+		}
+	}
+	links := make([]NodeLink, 0, len(s.links))
+	for _, link := range s.links {
+		if link.From != n && link.To != n {
+			links = append(links, link)
+		}
+	}
+	s.nodes = nodes
+	s.links = links
+	delete(s.nodesByID, n.ID())
+	delete(s.nodesJoinModes, n)
+	delete(s.nodesRateLimits, n)
+	delete(s.nodesBulkheads, n)
+	delete(s.nodesCircuitBreakers, n)
+	return true, nil
+}
+
+// RemoveLink remove the links from a node to another node from the system before activation.
+func (s *NodeSystem) RemoveLink(from, to Node) (bool, error) {
+	return s.removeLink(newNodeLink(from, to))
+}
+
+// RemoveLinkOnBranch remove the links from a node (on a specific branch) to another node from the system before activation.
+func (s *NodeSystem) RemoveLinkOnBranch(from, to Node, branch bool) (bool, error) {
+	return s.removeLink(newNodeLinkOnBranch(from, to, branch))
+}
+
+// ReplaceNode replace a node by another one into the system before activation.
+// The links from or to the replaced node, its join mode, and its limits, are moved to the new node.
+func (s *NodeSystem) ReplaceNode(old, replacement Node) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't replace node, node system is freeze due to activation")
+	}
+	if replacement == nil {
+		return false, errors.New("can't replace a node by a missing node")
+	}
+	if !s.haveNode(old) {
+		return false, fmt.Errorf("can't replace undeclared node: %+v", old)
+	}
+	if old == replacement {
+		return true, nil
+	}
+	if s.haveNode(replacement) {
+		return false, fmt.Errorf("can't replace a node by an already declared node: %+v", replacement)
+	}
+	if other, found := s.nodesByID[replacement.ID()]; found && other != old {
+		return false, fmt.Errorf("can't replace node, id '%v' is already used by another node", replacement.ID())
+	}
+	if old.DecideCapability() != replacement.DecideCapability() {
+		return false, fmt.Errorf("can't replace node '%+v' by node '%+v' with another decide capability", old, replacement)
+	}
+
+	for i, node := range s.nodes {
+		if node == old {
+			s.nodes[i] = replacement
+		}
+	}
+	delete(s.nodesByID, old.ID())
+	s.nodesByID[replacement.ID()] = replacement
+	for i, link := range s.links {
+		if link.From == old {
+			s.links[i].From = replacement
+		}
+		if link.To == old {
+			s.links[i].To = replacement
+		}
+	}
+	if mode, foundMode := s.nodesJoinModes[old]; foundMode {
+		delete(s.nodesJoinModes, old)
+		s.nodesJoinModes[replacement] = mode
+	}
+	if limiter, foundLimiter := s.nodesRateLimits[old]; foundLimiter {
+		delete(s.nodesRateLimits, old)
+		s.nodesRateLimits[replacement] = limiter
+	}
+	if bulkhead, foundBulkhead := s.nodesBulkheads[old]; foundBulkhead {
+		delete(s.nodesBulkheads, old)
+		s.nodesBulkheads[replacement] = bulkhead
+	}
+	if breaker, foundBreaker := s.nodesCircuitBreakers[old]; foundBreaker {
+		delete(s.nodesCircuitBreakers, old)
+		s.nodesCircuitBreakers[replacement] = breaker
+	}
+	return true, nil
+}
+
 // IsValid check if the configuration of the node system is valid based on checks.
 // Check for decision node with any node links as from,
 // check for cyclic redundancy in node links,
 // check for undeclared node used in node links,
-// check for multiple declaration of same node instance.
+// check for multiple declaration of same node instance,
+// check for multiple links to a node without join mode.
+// Each error have a dedicated type (like CycleError, or UndeclaredNodeError)
+// to be inspected with errors.As.
 func (s *NodeSystem) IsValid() (bool, []error) {
+	g := newNodeGraph(s)
 	errors := make([]error, 0)
-	errors = append(errors, checkForOrphanMultiBranchesNode(s)...)
-	errors = append(errors, checkForCyclicRedundancyInNodeLinks(s)...)
-	errors = append(errors, checkForUndeclaredNodeInNodeLink(s)...)
-	errors = append(errors, checkForMultipleInstanceOfSameNode(s)...)
-	errors = append(errors, checkForMultipleLinksToNodeWithoutJoinMode(s)...)
+	errors = append(errors, checkForOrphanMultiBranchesNode(s, g)...)
+	errors = append(errors, checkForCyclicRedundancyInNodeLinks(g)...)
+	errors = append(errors, checkForUndeclaredNodeInNodeLink(s, g)...)
+	errors = append(errors, checkForMultipleInstanceOfSameNode(g)...)
+	errors = append(errors, checkForMultipleLinksToNodeWithoutJoinMode(s, g)...)
 
 	if len(errors) == 0 {
 		return true, nil
@@ -104,7 +229,7 @@ func (s *NodeSystem) Activate() error {
 	followingNodesTree := make(map[Node]map[*bool][]Node)
 	ancestorsNodesTree := make(map[Node]map[*bool][]Node)
 
-	toNodes := make([]Node, 0)
+	toNodes := make(map[Node]bool)
 	for _, link := range s.links {
 		followingNodesTreeOnBranch, foundNode := followingNodesTree[link.From]
 		if !foundNode {
@@ -120,18 +245,11 @@ func (s *NodeSystem) Activate() error {
 		}
 		ancestorsNodesTreeOnBranch[link.Branch] = append(ancestorsNodesTreeOnBranch[link.Branch], link.From)
 
-		toNodes = append(toNodes, link.To)
+		toNodes[link.To] = true
 	}
 
 	for _, node := range s.nodes {
-		isInitialNode := true
-		for _, toNode := range toNodes {
-			if node == toNode {
-				isInitialNode = false
-				break
-			}
-		}
-		if isInitialNode {
+		if !toNodes[node] {
 			initialNodes = append(initialNodes, node)
 		}
 	}
@@ -144,6 +262,41 @@ func (s *NodeSystem) Activate() error {
 	return nil
 }
 
+// Deactivate unfreeze the node system to be able to modify it again.
+// A node system used by a computation, or an engine, must not be deactivated, use Clone instead.
+func (s *NodeSystem) Deactivate() {
+	s.activated = false
+	s.initialNodes = make([]Node, 0)
+	s.followingNodesTree = make(map[Node]map[*bool][]Node)
+	s.ancestorsNodesTree = make(map[Node]map[*bool][]Node)
+}
+
+// Clone create an unactivated copy of the node system (activated or not) to be modified.
+// The rate limits, bulkheads, and circuit breakers, of the copy are shared with the node system
+// (like when swapped, or configured as variant, in an engine).
+func (s *NodeSystem) Clone() *NodeSystem {
+	clone := NewNodeSystem()
+	clone.version = s.version
+	clone.nodes = append(clone.nodes, s.nodes...)
+	for id, node := range s.nodesByID {
+		clone.nodesByID[id] = node
+	}
+	clone.links = append(clone.links, s.links...)
+	for node, mode := range s.nodesJoinModes {
+		clone.nodesJoinModes[node] = mode
+	}
+	for node, limiter := range s.nodesRateLimits {
+		clone.nodesRateLimits[node] = limiter
+	}
+	for node, bulkhead := range s.nodesBulkheads {
+		clone.nodesBulkheads[node] = bulkhead
+	}
+	for node, breaker := range s.nodesCircuitBreakers {
+		clone.nodesCircuitBreakers[node] = breaker
+	}
+	return clone
+}
+
 // JoinModeOfNode get the configured join mode of a node
 func (s *NodeSystem) JoinModeOfNode(n Node) JoinMode {
 	mode, foundMode := s.nodesJoinModes[n]
@@ -153,6 +306,22 @@ func (s *NodeSystem) JoinModeOfNode(n Node) JoinMode {
 	return JoinNone
 }
 
+// Nodes get the declared nodes
+func (s *NodeSystem) Nodes() []Node {
+	return append(make([]Node, 0, len(s.nodes)), s.nodes...)
+}
+
+// NodeByID get the declared node with an identifier
+func (s *NodeSystem) NodeByID(id string) (Node, bool) {
+	node, found := s.nodesByID[id]
+	return node, found
+}
+
+// Links get the declared links between nodes
+func (s *NodeSystem) Links() []NodeLink {
+	return append(make([]NodeLink, 0, len(s.links)), s.links...)
+}
+
 // InitialNodes get the initial nodes
 func (s *NodeSystem) InitialNodes() []Node {
 	return s.initialNodes
@@ -227,149 +396,79 @@ func (s *NodeSystem) addLink(from, to Node, branch *bool) (bool, error) {
 	return true, nil
 }
 
-func (s *NodeSystem) haveNode(n Node) bool {
-	for _, node := range s.nodes {
-		if node == n {
-			return true
+func (s *NodeSystem) removeLink(link NodeLink) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't remove link, node system is freeze due to activation")
+	}
+
+	links := make([]NodeLink, 0, len(s.links))
+	for _, l := range s.links {
+		if l.From != link.From || l.To != link.To || l.Branch != link.Branch {
+			links = append(links, l)
 		}
 	}
-	return false
+	if len(links) == len(s.links) {
+		return false, fmt.Errorf("can't remove unknown link: %+v", link)
+	}
+	s.links = links
+	return true, nil
 }
 
-func checkForOrphanMultiBranchesNode(s *NodeSystem) []error {
-	errors := make([]error, 0)
-	for _, node := range s.nodes {
-		if node.DecideCapability() {
-			noLink := true
-			for _, link := range s.links {
-				if link.From == node {
-					noLink = false
-					break
-				}
-			}
-			if noLink {
-				errors = append(errors, fmt.Errorf("can't have decision node without link from it: %+v", node))
-			}
-		}
+// haveNode tell if the node itself is declared, another node with the same identifier being undeclared.
+func (s *NodeSystem) haveNode(n Node) bool {
+	if n == nil {
+		return false
 	}
-	return errors
+	node, found := s.nodesByID[n.ID()]
+	return found && node == n
 }
 
-func checkForCyclicRedundancyInNodeLinks(s *NodeSystem) []error {
+func checkForOrphanMultiBranchesNode(s *NodeSystem, g *nodeGraph) []error {
 	errors := make([]error, 0)
-	cycles := make([][]nodeLink, 0)
 	for _, node := range s.nodes {
-		possibleCycles := findCycle(s, node, node, nil)
-		cycles = append(cycles, possibleCycles...)
-	}
-
-	nodeLinkSliceComparator := cmp.Comparer(func(x, y []nodeLink) bool {
-		sameLinkCount := 0
-		for _, xItem := range x {
-			foundIt := false
-			for _, yItem := range y {
-				if cmp.Equal(xItem, yItem, nodeLinkComparator) {
-					foundIt = true
-					break
-				}
-			}
-			if foundIt {
-				sameLinkCount++
-			}
-		}
-		return sameLinkCount == len(x)
-	})
-
-	trimmedCycles := make([][]nodeLink, 0)
-	for _, cycle := range cycles {
-		alreadyTrimmed := false
-		for _, trimmedCycle := range trimmedCycles {
-			if cmp.Equal(cycle, trimmedCycle, nodeLinkSliceComparator) {
-				alreadyTrimmed = true
-			}
+		if node.DecideCapability() && len(g.outLinks[g.index[node]]) == 0 {
+			errors = append(errors, DecisionNodeWithoutLinkError{Node: node})
 		}
-		if !alreadyTrimmed {
-			trimmedCycles = append(trimmedCycles, cycle)
-		}
-	}
-
-	for _, cycle := range trimmedCycles {
-		errors = append(errors, fmt.Errorf("Can't have cycle in links between nodes: %+v", cycle))
 	}
 	return errors
 }
 
-func findCycle(s *NodeSystem, topNode, currentNode Node, walkednodeLinks []nodeLink) [][]nodeLink {
-	if walkednodeLinks != nil && len(walkednodeLinks) > 0 {
-		if topNode == currentNode {
-			return [][]nodeLink{walkednodeLinks}
-		}
-		for _, link := range walkednodeLinks {
-			if currentNode == link.From {
-				return [][]nodeLink{}
-			}
-		}
-	}
-	var selectedLinks []nodeLink
-	for _, link := range s.links {
-		if link.From == currentNode {
-			selectedLinks = append(selectedLinks, link)
-		}
-	}
-
-	if len(selectedLinks) == 0 {
-		return nil
-	}
-
-	cycles := make([][]nodeLink, 0)
-	for _, link := range selectedLinks {
-		newWalkednodeLinks := append(walkednodeLinks, link)
-		linkCycles := findCycle(s, topNode, link.To, newWalkednodeLinks)
-		cycles = append(cycles, linkCycles...)
+func checkForCyclicRedundancyInNodeLinks(g *nodeGraph) []error {
+	errors := make([]error, 0)
+	for _, cycle := range g.cycles() {
+		errors = append(errors, CycleError{Links: cycle})
 	}
-	return cycles
+	return errors
 }
 
-func checkForUndeclaredNodeInNodeLink(s *NodeSystem) []error {
+func checkForUndeclaredNodeInNodeLink(s *NodeSystem, g *nodeGraph) []error {
 	errors := make([]error, 0)
 	for _, link := range s.links {
-		if link.From != nil && !s.haveNode(link.From) {
-			errors = append(errors, fmt.Errorf("can't have undeclared node '%+v' as 'from' in branch link %+v", link.From, link))
+		if link.From != nil && !g.isDeclared(link.From) {
+			errors = append(errors, UndeclaredNodeError{Node: link.From, Link: link, Side: FromSide})
 		}
-		if link.To != nil && !s.haveNode(link.To) {
-			errors = append(errors, fmt.Errorf("can't have undeclared node '%+v' as 'to' in branch link %+v", link.To, link))
+		if link.To != nil && !g.isDeclared(link.To) {
+			errors = append(errors, UndeclaredNodeError{Node: link.To, Link: link, Side: ToSide})
 		}
 	}
 	return errors
 }
 
-func checkForMultipleInstanceOfSameNode(s *NodeSystem) []error {
+func checkForMultipleInstanceOfSameNode(g *nodeGraph) []error {
 	errors := make([]error, 0)
-	count := make(map[Node]int)
-	for i := 0; i < len(s.nodes); i++ {
-		for j := 0; j < len(s.nodes); j++ {
-			if i != j && s.nodes[i] == s.nodes[j] {
-				count[s.nodes[i]]++
-			}
-		}
-	}
-	for n, c := range count {
-		if c > 1 {
-			errors = append(errors, fmt.Errorf("can't have multiple instances (%v) of the same node: %+v", c, n))
+	for _, node := range g.nodes {
+		if count := g.declared[node]; count > 1 {
+			errors = append(errors, MultipleInstancesError{Node: node, Count: count})
 		}
 	}
 	return errors
 }
 
-func checkForMultipleLinksToNodeWithoutJoinMode(s *NodeSystem) []error {
+func checkForMultipleLinksToNodeWithoutJoinMode(s *NodeSystem, g *nodeGraph) []error {
 	errors := make([]error, 0)
-	count := make(map[Node]int)
-	for _, link := range s.links {
-		count[link.To]++
-	}
-	for n, c := range count {
-		if c > 1 && s.JoinModeOfNode(n) == JoinNone {
-			errors = append(errors, fmt.Errorf("can't have multiple links (%v) to the same node: %+v without join mode", c, n))
+	for i, node := range g.nodes {
+		if count := len(g.inLinks[i]); count > 1 && s.JoinModeOfNode(node) == JoinNone {
+			errors = append(errors, MissingJoinModeError{Node: node, Count: count})
 		}
 	}
 	return errors
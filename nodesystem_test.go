@@ -19,7 +19,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 		name                string
 		givenNodes          []Node
 		givenNodesJoinModes map[Node]JoinMode
-		givenLinks          []nodeLink
+		givenLinks          []NodeLink
 		expectedNodeSystem  *NodeSystem
 		expectedErrors      []error
 	}{
@@ -28,7 +28,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 		},
 		{
@@ -41,7 +41,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 		},
 		{
@@ -56,7 +56,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have multiple instances (2) of the same node: %+v", someActionNode),
@@ -72,7 +72,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					alwaysTrueDecisionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have decision node without link from it: %+v", alwaysTrueDecisionNode),
@@ -84,7 +84,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			expectedNodeSystem: &NodeSystem{
@@ -93,7 +93,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 				},
 			},
@@ -104,7 +104,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedNodeSystem: &NodeSystem{
@@ -113,20 +113,20 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					anotherActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 				},
 			},
 		},
 		{
 			name: "Can't add empty 'from' on branch link",
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				{To: someActionNode},
 			},
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have missing 'from' attribute"),
@@ -134,13 +134,13 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 		},
 		{
 			name: "Can't add empty 'to' on branch link",
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				{From: someActionNode},
 			},
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have missing 'to' attribute"),
@@ -148,13 +148,13 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 		},
 		{
 			name: "Can't add link with the node on 'from' and 'to'",
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, someActionNode),
 			},
 			expectedNodeSystem: &NodeSystem{
 				nodes:          []Node{},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have link on from and to the same node"),
@@ -167,7 +167,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 			},
@@ -178,7 +178,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					anotherActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 				},
@@ -194,7 +194,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				anotherActionNode: JoinAnd,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 				newNodeLink(someActionNode, anotherActionNode),
 			},
@@ -207,7 +207,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodesJoinModes: map[Node]JoinMode{
 					anotherActionNode: JoinAnd,
 				},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 					newNodeLink(someActionNode, anotherActionNode),
 				},
@@ -223,7 +223,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			givenNodesJoinModes: map[Node]JoinMode{
 				anotherActionNode: JoinOr,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 				newNodeLink(someActionNode, anotherActionNode),
 			},
@@ -236,7 +236,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodesJoinModes: map[Node]JoinMode{
 					anotherActionNode: JoinOr,
 				},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 					newNodeLink(someActionNode, anotherActionNode),
 				},
@@ -248,7 +248,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(someActionNode, anotherActionNode, true),
 			},
 			expectedNodeSystem: &NodeSystem{
@@ -257,7 +257,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					anotherActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have not needed branch"),
@@ -269,7 +269,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(alwaysTrueDecisionNode, someActionNode),
 			},
 			expectedNodeSystem: &NodeSystem{
@@ -278,7 +278,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links:          []nodeLink{},
+				links:          []NodeLink{},
 			},
 			expectedErrors: []error{
 				fmt.Errorf("can't have missing branch"),
@@ -290,7 +290,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			givenNodes: []Node{
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedNodeSystem: &NodeSystem{
@@ -298,7 +298,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					someActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 				},
 			},
@@ -311,7 +311,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 			givenNodes: []Node{
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedNodeSystem: &NodeSystem{
@@ -319,7 +319,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					anotherActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 				},
 			},
@@ -333,7 +333,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 				newNodeLink(anotherActionNode, someActionNode),
 			},
@@ -343,13 +343,13 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					anotherActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 					newNodeLink(anotherActionNode, someActionNode),
 				},
 			},
 			expectedErrors: []error{
-				fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
+				fmt.Errorf("Can't have cycle in links between nodes: %+v", []NodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 					newNodeLink(anotherActionNode, someActionNode),
 				}),
@@ -362,7 +362,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 				newNodeLink(someActionNode, anotherActionNode),
 				newNodeLink(anotherActionNode, someActionNode),
@@ -379,14 +379,14 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				nodesJoinModes: map[Node]JoinMode{
 					someActionNode: JoinAnd,
 				},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 					newNodeLink(someActionNode, anotherActionNode),
 					newNodeLink(anotherActionNode, someActionNode),
 				},
 			},
 			expectedErrors: []error{
-				fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
+				fmt.Errorf("Can't have cycle in links between nodes: %+v", []NodeLink{
 					newNodeLink(someActionNode, anotherActionNode),
 					newNodeLink(anotherActionNode, someActionNode),
 				}),
@@ -399,7 +399,7 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false),
 				newNodeLink(anotherActionNode, alwaysTrueDecisionNode),
@@ -411,14 +411,14 @@ func Test_NodeSystem_IsValid(t *testing.T) {
 					anotherActionNode,
 				},
 				nodesJoinModes: map[Node]JoinMode{},
-				links: []nodeLink{
+				links: []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false),
 					newNodeLink(anotherActionNode, alwaysTrueDecisionNode),
 				},
 			},
 			expectedErrors: []error{
-				fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
+				fmt.Errorf("Can't have cycle in links between nodes: %+v", []NodeLink{
 					newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, false),
 					newNodeLink(anotherActionNode, alwaysTrueDecisionNode),
 				}),
@@ -448,10 +448,10 @@ func Test_NodeSystem_Activate(t *testing.T) {
 		name                               string
 		givenNodes                         []Node
 		givenNodesJoinModes                map[Node]JoinMode
-		givenLinks                         []nodeLink
+		givenLinks                         []NodeLink
 		givenNodesAfterActivation          []Node
 		givenNodesJoinModesAfterActivation map[Node]JoinMode
-		givenLinksAfterActivation          []nodeLink
+		givenLinksAfterActivation          []NodeLink
 		expectedActivatation               bool
 		expectedInitialNodes               []Node
 		expectedFollowingNodesTree         map[Node]map[*bool][]Node
@@ -467,7 +467,7 @@ func Test_NodeSystem_Activate(t *testing.T) {
 		},
 		{
 			name: "Can't activate an unvalidated system",
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedActivatation: false,
@@ -488,7 +488,7 @@ func Test_NodeSystem_Activate(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedActivatation: true,
@@ -508,7 +508,7 @@ func Test_NodeSystem_Activate(t *testing.T) {
 				anotherActionNode,
 				alwaysTrueDecisionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
 			},
 			expectedActivatation: true,
@@ -548,7 +548,7 @@ func Test_NodeSystem_Activate(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinksAfterActivation: []nodeLink{
+			givenLinksAfterActivation: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedActivatation: true,
@@ -609,7 +609,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 	testCases := []struct {
 		name                   string
 		givenNodes             []Node
-		givenLinks             []nodeLink
+		givenLinks             []NodeLink
 		givenNode              Node
 		givenBranch            *bool
 		expectedFollowingNodes []Node
@@ -617,7 +617,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 	}{
 		{
 			name: "Can't follow on an unactivated system",
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedError: errors.New("can't follow a node if system is not activated"),
@@ -628,7 +628,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			givenNode:              someActionNode,
@@ -640,7 +640,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			givenNode:              anotherActionNode,
@@ -652,7 +652,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:              alwaysTrueDecisionNode,
@@ -665,7 +665,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:              someActionNode,
@@ -677,7 +677,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:              alwaysTrueDecisionNode,
@@ -689,7 +689,7 @@ func Test_NodeSystem_Follow(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:              alwaysTrueDecisionNode,
@@ -727,7 +727,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 	testCases := []struct {
 		name                  string
 		givenNodes            []Node
-		givenLinks            []nodeLink
+		givenLinks            []NodeLink
 		givenNode             Node
 		givenBranch           *bool
 		expectedAncestorNodes []Node
@@ -735,7 +735,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 	}{
 		{
 			name: "Can't get ancestors on an unactivated system",
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			expectedError: errors.New("can't get ancestors of a node if system is not activated"),
@@ -746,7 +746,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			givenNode:             someActionNode,
@@ -758,7 +758,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 				someActionNode,
 				anotherActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLink(someActionNode, anotherActionNode),
 			},
 			givenNode:             anotherActionNode,
@@ -770,7 +770,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:             alwaysTrueDecisionNode,
@@ -782,7 +782,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:             someActionNode,
@@ -795,7 +795,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:             someActionNode,
@@ -807,7 +807,7 @@ func Test_NodeSystem_Ancestors(t *testing.T) {
 				alwaysTrueDecisionNode,
 				someActionNode,
 			},
-			givenLinks: []nodeLink{
+			givenLinks: []NodeLink{
 				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
 			},
 			givenNode:             someActionNode,
@@ -925,7 +925,7 @@ func Test_Github_Issue_16(t *testing.T) {
 	_, errs := ns.IsValid()
 
 	expectedErrors := []error{
-		fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
+		fmt.Errorf("Can't have cycle in links between nodes: %+v", []NodeLink{
 			newNodeLink(a2, a3),
 			newNodeLink(a3, a2),
 		}),
@@ -989,11 +989,11 @@ func Test_multiple_cycles(t *testing.T) {
 	_, errs := ns.IsValid()
 
 	expectedErrors := []error{
-		fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
+		fmt.Errorf("Can't have cycle in links between nodes: %+v", []NodeLink{
 			newNodeLink(a2, a3),
 			newNodeLink(a3, a2),
 		}),
-		fmt.Errorf("Can't have cycle in links between nodes: %+v", []nodeLink{
+		fmt.Errorf("Can't have cycle in links between nodes: %+v", []NodeLink{
 			newNodeLink(a5, a6),
 			newNodeLink(a6, a7),
 			newNodeLink(a7, a5),
@@ -1005,7 +1005,194 @@ func Test_multiple_cycles(t *testing.T) {
 	}
 }
 
-func loadNodeSystem(system *NodeSystem, nodes []Node, nodesJoinModes map[Node]JoinMode, links []nodeLink) []error {
+func Test_NodeSystem_RemoveNode(t *testing.T) {
+	system := NewNodeSystem()
+	loadNodeSystem(system,
+		[]Node{alwaysTrueDecisionNode, someActionNode, anotherActionNode},
+		map[Node]JoinMode{anotherActionNode: JoinAnd, someActionNode: JoinOr},
+		[]NodeLink{
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
+			newNodeLink(someActionNode, anotherActionNode),
+		})
+
+	ok, err := system.RemoveNode(someActionNode)
+
+	expectedNodeSystem := &NodeSystem{
+		nodes:          []Node{alwaysTrueDecisionNode, anotherActionNode},
+		nodesJoinModes: map[Node]JoinMode{anotherActionNode: JoinAnd},
+		links: []NodeLink{
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true),
+		},
+	}
+	if !ok || err != nil {
+		t.Errorf("remove - got: %+v, %+v", ok, err)
+	}
+	if !cmp.Equal(system, expectedNodeSystem) {
+		t.Errorf("system - got: %+v, want: %+v", system, expectedNodeSystem)
+	}
+
+	_, err = system.RemoveNode(someActionNode)
+	expectedError := fmt.Errorf("can't remove undeclared node: %+v", someActionNode)
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	sameID, _ := NewActionNode(anotherActionNode.ID(), func(*Context) error { return nil })
+	_, err = system.RemoveNode(sameID)
+	expectedError = fmt.Errorf("can't remove undeclared node: %+v", sameID)
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error of node with a declared id - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_NodeSystem_RemoveLink(t *testing.T) {
+	system := NewNodeSystem()
+	loadNodeSystem(system,
+		[]Node{alwaysTrueDecisionNode, someActionNode, anotherActionNode},
+		nil,
+		[]NodeLink{
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, false),
+			newNodeLink(someActionNode, anotherActionNode),
+		})
+
+	testCases := []struct {
+		name          string
+		remove        func() (bool, error)
+		expectedLinks []NodeLink
+		expectedError error
+	}{
+		{
+			name:   "Can remove a link on a branch",
+			remove: func() (bool, error) { return system.RemoveLinkOnBranch(alwaysTrueDecisionNode, someActionNode, false) },
+			expectedLinks: []NodeLink{
+				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
+				newNodeLink(someActionNode, anotherActionNode),
+			},
+		},
+		{
+			name:   "Can remove a link",
+			remove: func() (bool, error) { return system.RemoveLink(someActionNode, anotherActionNode) },
+			expectedLinks: []NodeLink{
+				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
+			},
+		},
+		{
+			name:   "Can't remove an unknown link",
+			remove: func() (bool, error) { return system.RemoveLink(someActionNode, anotherActionNode) },
+			expectedLinks: []NodeLink{
+				newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
+			},
+			expectedError: fmt.Errorf("can't remove unknown link: %+v", newNodeLink(someActionNode, anotherActionNode)),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := testCase.remove()
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if !cmp.Equal(system.links, testCase.expectedLinks, nodeLinkComparator) {
+				t.Errorf("links - got: %+v, want: %+v", system.links, testCase.expectedLinks)
+			}
+		})
+	}
+}
+
+func Test_NodeSystem_ReplaceNode(t *testing.T) {
+	replacementActionNode, _ := NewActionNode("replacementActionNode", func(*Context) error { return nil })
+
+	system := NewNodeSystem()
+	loadNodeSystem(system,
+		[]Node{alwaysTrueDecisionNode, someActionNode, anotherActionNode},
+		map[Node]JoinMode{someActionNode: JoinOr},
+		[]NodeLink{
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, someActionNode, true),
+			newNodeLink(someActionNode, anotherActionNode),
+		})
+
+	ok, err := system.ReplaceNode(someActionNode, replacementActionNode)
+
+	expectedNodeSystem := &NodeSystem{
+		nodes:          []Node{alwaysTrueDecisionNode, replacementActionNode, anotherActionNode},
+		nodesJoinModes: map[Node]JoinMode{replacementActionNode: JoinOr},
+		links: []NodeLink{
+			newNodeLinkOnBranch(alwaysTrueDecisionNode, replacementActionNode, true),
+			newNodeLink(replacementActionNode, anotherActionNode),
+		},
+	}
+	if !ok || err != nil {
+		t.Errorf("replace - got: %+v, %+v", ok, err)
+	}
+	if !cmp.Equal(system, expectedNodeSystem) {
+		t.Errorf("system - got: %+v, want: %+v", system, expectedNodeSystem)
+	}
+
+	_, err = system.ReplaceNode(anotherActionNode, alwaysTrueDecisionNode)
+	expectedError := fmt.Errorf("can't replace a node by an already declared node: %+v", alwaysTrueDecisionNode)
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_NodeSystem_Clone(t *testing.T) {
+	system := NewNodeSystem()
+	loadNodeSystem(system,
+		[]Node{someActionNode, anotherActionNode},
+		nil,
+		[]NodeLink{newNodeLink(someActionNode, anotherActionNode)})
+	system.Activate()
+
+	clone := system.Clone()
+	if clone.IsActivated() {
+		t.Errorf("clone must not be activated")
+	}
+	if _, err := clone.RemoveNode(anotherActionNode); err != nil {
+		t.Errorf("clone must be editable, got: %+v", err)
+	}
+	if len(system.nodes) != 2 || len(system.links) != 1 {
+		t.Errorf("system must not be modified by its clone, got: %+v", system)
+	}
+
+	system.Deactivate()
+	if system.IsActivated() || len(system.InitialNodes()) != 0 {
+		t.Errorf("system must be deactivated, got: %+v", system)
+	}
+	if _, err := system.RemoveNode(anotherActionNode); err != nil {
+		t.Errorf("deactivated system must be editable, got: %+v", err)
+	}
+}
+
+func Test_NodeSystem_AddNode_ids(t *testing.T) {
+	sameIDActionNode, _ := NewActionNode("someActionNode", func(*Context) error { return nil })
+
+	system := NewNodeSystem()
+	system.AddNode(someActionNode)
+
+	_, err := system.AddNode(sameIDActionNode)
+	expectedError := errors.New("can't add node, id 'someActionNode' is already used by another node")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	_, err = system.AddNode(nil)
+	expectedError = errors.New("can't add a missing node")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	node, found := system.NodeByID("someActionNode")
+	if !found || node != someActionNode {
+		t.Errorf("node by id - got: %+v, want: %+v", node, someActionNode)
+	}
+	if _, found := system.NodeByID("sameIDActionNode"); found {
+		t.Errorf("node by id - must not found an unknown id")
+	}
+}
+
+func loadNodeSystem(system *NodeSystem, nodes []Node, nodesJoinModes map[Node]JoinMode, links []NodeLink) []error {
 	var errs []error
 	for _, node := range nodes {
 		_, err := system.AddNode(node)
@@ -0,0 +1,166 @@
+package hoff
+
+import (
+	"errors"
+	"time"
+)
+
+// ExecutionPlan describe the order in which the nodes of an activated NodeSystem can be computed.
+type ExecutionPlan struct {
+	// Levels group the nodes by topological level.
+	// The nodes of a level only depend on nodes of the previous levels,
+	// so they can be computed at the same time.
+	Levels [][]Node
+
+	dependencies map[Node][]Node
+	dependents   map[Node][]Node
+	levelOfNodes map[Node]int
+}
+
+// ExecutionPlan get the execution plan of the node system after activation.
+func (s *NodeSystem) ExecutionPlan() (*ExecutionPlan, error) {
+	if !s.activated {
+		return nil, errors.New("can't plan the execution if system is not activated")
+	}
+
+	plan := &ExecutionPlan{
+		Levels:       make([][]Node, 0),
+		dependencies: make(map[Node][]Node),
+		dependents:   make(map[Node][]Node),
+		levelOfNodes: make(map[Node]int),
+	}
+	for _, link := range s.links {
+		plan.dependencies[link.To] = appendMissingNode(plan.dependencies[link.To], link.From)
+		plan.dependents[link.From] = appendMissingNode(plan.dependents[link.From], link.To)
+	}
+
+	remainingDependencies := make(map[Node]int)
+	levelNodes := make([]Node, 0)
+	for _, node := range s.nodes {
+		remainingDependencies[node] = len(plan.dependencies[node])
+		if remainingDependencies[node] == 0 {
+			levelNodes = append(levelNodes, node)
+		}
+	}
+
+	for len(levelNodes) > 0 {
+		level := len(plan.Levels)
+		plan.Levels = append(plan.Levels, levelNodes)
+
+		readyNodes := make(map[Node]bool)
+		for _, node := range levelNodes {
+			plan.levelOfNodes[node] = level
+			for _, dependent := range plan.dependents[node] {
+				remainingDependencies[dependent]--
+				if remainingDependencies[dependent] == 0 {
+					readyNodes[dependent] = true
+				}
+			}
+		}
+
+		levelNodes = make([]Node, 0)
+		for _, node := range s.nodes {
+			if readyNodes[node] {
+				levelNodes = append(levelNodes, node)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// Order get all the nodes in a topological order, level after level.
+func (p *ExecutionPlan) Order() []Node {
+	nodes := make([]Node, 0)
+	for _, level := range p.Levels {
+		nodes = append(nodes, level...)
+	}
+	return nodes
+}
+
+// Level get the topological level of a node, or -1 if the node is not in the plan.
+func (p *ExecutionPlan) Level(n Node) int {
+	level, found := p.levelOfNodes[n]
+	if !found {
+		return -1
+	}
+	return level
+}
+
+// Dependencies get the nodes who need to be computed before a node, whatever their branch.
+func (p *ExecutionPlan) Dependencies(n Node) []Node {
+	return p.dependencies[n]
+}
+
+// Concurrent get the nodes who can be computed at the same time as a node,
+// i.e. the nodes who are not a direct, or transitive, dependency or dependent of it.
+func (p *ExecutionPlan) Concurrent(n Node) []Node {
+	if _, found := p.levelOfNodes[n]; !found {
+		return nil
+	}
+	related := make(map[Node]bool)
+	walkNodes(n, p.dependencies, related)
+	walkNodes(n, p.dependents, related)
+
+	nodes := make([]Node, 0)
+	for _, node := range p.Order() {
+		if node != n && !related[node] {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// CriticalPath get the longest path of nodes (and its cost) based on the estimated cost of each node.
+// A node without estimated cost is considered as free.
+func (p *ExecutionPlan) CriticalPath(costs map[Node]time.Duration) ([]Node, time.Duration) {
+	pathCosts := make(map[Node]time.Duration)
+	previousNodes := make(map[Node]Node)
+
+	var lastNode Node
+	var longestCost time.Duration
+	for _, node := range p.Order() {
+		var previousNode Node
+		var previousCost time.Duration
+		for _, dependency := range p.dependencies[node] {
+			if previousNode == nil || pathCosts[dependency] > previousCost {
+				previousNode = dependency
+				previousCost = pathCosts[dependency]
+			}
+		}
+		pathCosts[node] = previousCost + costs[node]
+		if previousNode != nil {
+			previousNodes[node] = previousNode
+		}
+		if lastNode == nil || pathCosts[node] > longestCost {
+			lastNode = node
+			longestCost = pathCosts[node]
+		}
+	}
+
+	if lastNode == nil {
+		return nil, 0
+	}
+	path := []Node{lastNode}
+	for node, found := previousNodes[lastNode]; found; node, found = previousNodes[node] {
+		path = append([]Node{node}, path...)
+	}
+	return path, longestCost
+}
+
+func walkNodes(n Node, tree map[Node][]Node, walked map[Node]bool) {
+	for _, node := range tree[n] {
+		if !walked[node] {
+			walked[node] = true
+			walkNodes(node, tree, walked)
+		}
+	}
+}
+
+func appendMissingNode(nodes []Node, n Node) []Node {
+	for _, node := range nodes {
+		if node == n {
+			return nodes
+		}
+	}
+	return append(nodes, n)
+}
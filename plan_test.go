@@ -0,0 +1,91 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ExecutionPlan(t *testing.T) {
+	action1, _ := NewActionNode("action1", func(*Context) error { return nil })
+	decision2, _ := NewDecisionNode("decision2", func(*Context) (bool, error) { return true, nil })
+	action3, _ := NewActionNode("action3", func(*Context) error { return nil })
+	action4, _ := NewActionNode("action4", func(*Context) error { return nil })
+	action5, _ := NewActionNode("action5", func(*Context) error { return nil })
+
+	ns := NewNodeSystem()
+	ns.AddNode(action5)
+	ns.AddNode(action4)
+	ns.AddNode(action3)
+	ns.AddNode(decision2)
+	ns.AddNode(action1)
+	ns.AddLink(action1, decision2)
+	ns.AddLinkOnBranch(decision2, action3, true)
+	ns.AddLinkOnBranch(decision2, action4, false)
+	ns.AddLink(action3, action5)
+	ns.AddLink(action1, action5)
+	ns.ConfigureJoinModeOnNode(action5, JoinAnd)
+	ns.Activate()
+
+	plan, err := ns.ExecutionPlan()
+	if err != nil {
+		t.Fatalf("error - got: %+v", err)
+	}
+
+	expectedLevels := [][]Node{
+		{action1},
+		{decision2},
+		{action4, action3},
+		{action5},
+	}
+	if !cmp.Equal(plan.Levels, expectedLevels, NodeComparator) {
+		t.Errorf("levels - got: %+v, want: %+v", plan.Levels, expectedLevels)
+	}
+
+	expectedOrder := []Node{action1, decision2, action4, action3, action5}
+	if !cmp.Equal(plan.Order(), expectedOrder, NodeComparator) {
+		t.Errorf("order - got: %+v, want: %+v", plan.Order(), expectedOrder)
+	}
+
+	if plan.Level(action3) != 2 {
+		t.Errorf("level - got: %+v, want: %+v", plan.Level(action3), 2)
+	}
+
+	expectedDependencies := []Node{action3, action1}
+	if !cmp.Equal(plan.Dependencies(action5), expectedDependencies, NodeComparator) {
+		t.Errorf("dependencies - got: %+v, want: %+v", plan.Dependencies(action5), expectedDependencies)
+	}
+
+	expectedConcurrent := []Node{action3, action5}
+	if !cmp.Equal(plan.Concurrent(action4), expectedConcurrent, NodeComparator) {
+		t.Errorf("concurrent - got: %+v, want: %+v", plan.Concurrent(action4), expectedConcurrent)
+	}
+
+	path, cost := plan.CriticalPath(map[Node]time.Duration{
+		action1: time.Second,
+		action3: time.Second,
+		action4: 5 * time.Second,
+		action5: time.Second,
+	})
+	expectedPath := []Node{action1, decision2, action4}
+	if !cmp.Equal(path, expectedPath, NodeComparator) {
+		t.Errorf("critical path - got: %+v, want: %+v", path, expectedPath)
+	}
+	if cost != 6*time.Second {
+		t.Errorf("critical path cost - got: %+v, want: %+v", cost, 6*time.Second)
+	}
+}
+
+func Test_NodeSystem_ExecutionPlan_not_activated(t *testing.T) {
+	plan, err := NewNodeSystem().ExecutionPlan()
+
+	expectedError := errors.New("can't plan the execution if system is not activated")
+	if plan != nil {
+		t.Errorf("plan - got: %+v, want: nil", plan)
+	}
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
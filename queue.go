@@ -0,0 +1,286 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SheddingPolicy define how the queue of an engine behave when a job is submitted while the queue is full.
+type SheddingPolicy string
+
+const (
+	// ShedReject will reject the submitted job.
+	ShedReject SheddingPolicy = "reject"
+	// ShedDropOldest will drop the oldest queued job of the lowest priority to queue the submitted job,
+	// or reject the submitted job when its priority is lower than the queued jobs ones.
+	ShedDropOldest = "drop_oldest"
+	// ShedBlock will make the submission wait for a free place in the queue.
+	ShedBlock = "block"
+)
+
+var (
+	// ErrQueueFull is the error of a job rejected by the full queue of an engine.
+	ErrQueueFull = errors.New("can't submit job, queue is full")
+	// ErrJobDropped is the error of a job dropped from the full queue of an engine.
+	ErrJobDropped = errors.New("job dropped from the full queue")
+	// ErrQueueStopped is the error of a job submitted to, or queued in, the stopped queue of an engine.
+	ErrQueueStopped = errors.New("queue stopped")
+)
+
+// QueueMetrics give the depth of the queue of an engine, and the count of admitted, rejected, and dropped jobs.
+type QueueMetrics struct {
+	Depth    int
+	Capacity int
+	Admitted int
+	Rejected int
+	Dropped  int
+}
+
+// ConfigureQueue make the submitted jobs go through a bounded queue (only once),
+// computed by a fixed number of workers, the jobs of higher priority being computed first.
+func (e *Engine) ConfigureQueue(capacity, workers int, policy SheddingPolicy) error {
+	if capacity < 1 {
+		return fmt.Errorf("can't configure queue without positive capacity: %v", capacity)
+	}
+	if workers < 1 {
+		return fmt.Errorf("can't configure queue without positive workers: %v", workers)
+	}
+	switch policy {
+	case ShedReject, ShedDropOldest, ShedBlock:
+	default:
+		return fmt.Errorf("can't configure queue with unknown shedding policy: %v", policy)
+	}
+
+	e.jobsMutex.Lock()
+	defer e.jobsMutex.Unlock()
+	if e.queue != nil {
+		return errors.New("queue already configured")
+	}
+	e.queue = newJobQueue(capacity, policy)
+	e.queue.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go e.work(e.queue)
+	}
+	return nil
+}
+
+// StopQueue stop the queue of the engine, the queued jobs being cancelled, and the submissions failing with ErrQueueStopped,
+// then wait for the workers to finish their running jobs, or for the context to be done.
+func (e *Engine) StopQueue(ctx context.Context) error {
+	e.jobsMutex.Lock()
+	queue := e.queue
+	e.jobsMutex.Unlock()
+	if queue == nil {
+		return errors.New("can't stop queue without configured queue")
+	}
+
+	stopped := queue.stop()
+	e.jobsMutex.Lock()
+	for _, entry := range stopped {
+		e.cancelQueuedJob(entry.job, ErrQueueStopped)
+	}
+	e.jobsMutex.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		queue.workers.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// QueueMetrics get the metrics of the queue of the engine (empty without queue).
+func (e *Engine) QueueMetrics() QueueMetrics {
+	e.jobsMutex.Lock()
+	queue := e.queue
+	e.jobsMutex.Unlock()
+	if queue == nil {
+		return QueueMetrics{}
+	}
+	return queue.metrics()
+}
+
+func (e *Engine) work(queue *jobQueue) {
+	defer queue.workers.Done()
+	for {
+		entry, found := queue.pop()
+		if !found {
+			return
+		}
+		e.runJob(entry.ctx, entry.job, entry.data)
+	}
+}
+
+// cancelQueuedJob finish a job removed from the queue before its computation (under the jobs mutex).
+func (e *Engine) cancelQueuedJob(job Job, err error) {
+	job.Status = JobCancelled
+	job.Result = ComputationResult{Error: err}
+	e.finishJob(job)
+}
+
+// queuedJob is a job waiting in the queue of an engine.
+type queuedJob struct {
+	job  Job
+	ctx  context.Context
+	data map[string]interface{}
+}
+
+// jobQueue is a bounded priority queue of jobs, the jobs of same priority being in submission order.
+type jobQueue struct {
+	mutex    sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	capacity int
+	policy   SheddingPolicy
+	entries  []queuedJob
+	counts   QueueMetrics
+	stopped  bool
+	workers  sync.WaitGroup
+}
+
+func newJobQueue(capacity int, policy SheddingPolicy) *jobQueue {
+	q := &jobQueue{
+		capacity: capacity,
+		policy:   policy,
+		entries:  make([]queuedJob, 0, capacity),
+	}
+	q.notEmpty = sync.NewCond(&q.mutex)
+	q.notFull = sync.NewCond(&q.mutex)
+	return q
+}
+
+// push add a job to the queue following the shedding policy when the queue is full,
+// and give the dropped job (if any).
+// With ShedBlock policy, the context stop the wait for a free place.
+func (q *jobQueue) push(ctx context.Context, entry queuedJob) (*queuedJob, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.stopped {
+		return nil, ErrQueueStopped
+	}
+
+	var dropped *queuedJob
+	if len(q.entries) >= q.capacity {
+		switch q.policy {
+		case ShedReject:
+			q.counts.Rejected++
+			return nil, ErrQueueFull
+		case ShedDropOldest:
+			victim := q.lowest()
+			if entry.job.Priority < q.entries[victim].job.Priority {
+				q.counts.Rejected++
+				return nil, ErrQueueFull
+			}
+			dropped = &queuedJob{}
+			*dropped = q.entries[victim]
+			q.entries = append(q.entries[:victim], q.entries[victim+1:]...)
+			q.counts.Dropped++
+		case ShedBlock:
+			if ctx.Done() != nil {
+				// wake up the waiting submission once its context is done
+				waiting := make(chan struct{})
+				defer close(waiting)
+				go func() {
+					select {
+					case <-ctx.Done():
+						q.mutex.Lock()
+						q.notFull.Broadcast()
+						q.mutex.Unlock()
+					case <-waiting:
+					}
+				}()
+			}
+			for len(q.entries) >= q.capacity && !q.stopped && ctx.Err() == nil {
+				q.notFull.Wait()
+			}
+			if q.stopped {
+				return nil, ErrQueueStopped
+			}
+			if err := ctx.Err(); err != nil {
+				q.counts.Rejected++
+				return nil, err
+			}
+		}
+	}
+
+	q.entries = append(q.entries, entry)
+	q.counts.Admitted++
+	q.notEmpty.Signal()
+	return dropped, nil
+}
+
+// pop remove the oldest job of the highest priority from the queue, waiting for a job if the queue is empty,
+// or give nothing once the queue is stopped.
+func (q *jobQueue) pop() (queuedJob, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for len(q.entries) == 0 && !q.stopped {
+		q.notEmpty.Wait()
+	}
+	if q.stopped {
+		return queuedJob{}, false
+	}
+	next := 0
+	for i, entry := range q.entries {
+		if entry.job.Priority > q.entries[next].job.Priority {
+			next = i
+		}
+	}
+	entry := q.entries[next]
+	q.entries = append(q.entries[:next], q.entries[next+1:]...)
+	q.notFull.Broadcast()
+	return entry, true
+}
+
+// remove a job from the queue, if still queued.
+func (q *jobQueue) remove(id string) (queuedJob, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for i, entry := range q.entries {
+		if entry.job.ID == id {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			q.notFull.Broadcast()
+			return entry, true
+		}
+	}
+	return queuedJob{}, false
+}
+
+// stop the queue, and give the removed queued jobs.
+func (q *jobQueue) stop() []queuedJob {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.stopped = true
+	entries := q.entries
+	q.entries = nil
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	return entries
+}
+
+// lowest give the index of the oldest job of the lowest priority.
+func (q *jobQueue) lowest() int {
+	lowest := 0
+	for i, entry := range q.entries {
+		if entry.job.Priority < q.entries[lowest].job.Priority {
+			lowest = i
+		}
+	}
+	return lowest
+}
+
+func (q *jobQueue) metrics() QueueMetrics {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	metrics := q.counts
+	metrics.Depth = len(q.entries)
+	metrics.Capacity = q.capacity
+	return metrics
+}
@@ -0,0 +1,269 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigureQueue(t *testing.T) {
+	testCases := []struct {
+		name          string
+		givenCapacity int
+		givenWorkers  int
+		givenPolicy   SheddingPolicy
+		expectedError error
+	}{
+		{
+			name:          "Can configure a queue",
+			givenCapacity: 10,
+			givenWorkers:  2,
+			givenPolicy:   ShedBlock,
+		},
+		{
+			name:          "Can't configure a queue without capacity",
+			givenWorkers:  2,
+			givenPolicy:   ShedReject,
+			expectedError: errors.New("can't configure queue without positive capacity: 0"),
+		},
+		{
+			name:          "Can't configure a queue without workers",
+			givenCapacity: 10,
+			givenPolicy:   ShedReject,
+			expectedError: errors.New("can't configure queue without positive workers: 0"),
+		},
+		{
+			name:          "Can't configure a queue with an unknown shedding policy",
+			givenCapacity: 10,
+			givenWorkers:  2,
+			givenPolicy:   "drop_newest",
+			expectedError: errors.New("can't configure queue with unknown shedding policy: drop_newest"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			eng := NewEngine(SequentialComputation)
+			err := eng.ConfigureQueue(testCase.givenCapacity, testCase.givenWorkers, testCase.givenPolicy)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			expectedMetrics := QueueMetrics{}
+			if err == nil {
+				expectedMetrics.Capacity = testCase.givenCapacity
+			}
+			if metrics := eng.QueueMetrics(); !cmp.Equal(metrics, expectedMetrics) {
+				t.Errorf("metrics - got: %+v, want: %+v", metrics, expectedMetrics)
+			}
+		})
+	}
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureQueue(10, 2, ShedBlock)
+	err := eng.ConfigureQueue(10, 2, ShedBlock)
+	expectedError := errors.New("queue already configured")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+// queuedEngine create an engine with a queue of one worker, busy with a job until the release of the returned channel,
+// and give the names of the computed jobs in order.
+func queuedEngine(t *testing.T, capacity int, policy SheddingPolicy) (*Engine, chan struct{}, func() []string) {
+	var mutex sync.Mutex
+	names := make([]string, 0)
+	release := make(chan struct{})
+	recordName, _ := NewActionNode("recordName", func(c *Context) error {
+		name, _ := c.Read("name")
+		if name == "busy" {
+			<-release
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		names = append(names, name.(string))
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(recordName)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureQueue(capacity, 1, policy)
+	busy, _ := eng.Submit(map[string]interface{}{"name": "busy"})
+	waitForJobStatus(t, eng, busy, JobRunning)
+
+	return eng, release, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append(make([]string, 0, len(names)), names...)
+	}
+}
+
+func submitNamed(t *testing.T, eng *Engine, name string, priority int) string {
+	t.Helper()
+	id, err := eng.SubmitWithPriority(context.Background(), map[string]interface{}{"name": name}, priority)
+	if err != nil {
+		t.Fatalf("submit error of %v - got: %+v", name, err)
+	}
+	return id
+}
+
+func Test_Engine_Queue_priority(t *testing.T) {
+	eng, release, names := queuedEngine(t, 10, ShedReject)
+	bulk := submitNamed(t, eng, "bulk", 0)
+	submitNamed(t, eng, "premium", 10)
+	submitNamed(t, eng, "standard", 5)
+	submitNamed(t, eng, "otherPremium", 10)
+
+	if metrics := eng.QueueMetrics(); !cmp.Equal(metrics, QueueMetrics{Depth: 4, Capacity: 10, Admitted: 5}) {
+		t.Errorf("metrics - got: %+v", metrics)
+	}
+	close(release)
+	waitForJobStatus(t, eng, bulk, JobDone)
+
+	expectedNames := []string{"busy", "premium", "otherPremium", "standard", "bulk"}
+	if !cmp.Equal(names(), expectedNames) {
+		t.Errorf("names - got: %v, want: %v", names(), expectedNames)
+	}
+}
+
+func Test_Engine_Queue_reject(t *testing.T) {
+	eng, release, _ := queuedEngine(t, 1, ShedReject)
+	defer close(release)
+	submitNamed(t, eng, "queued", 0)
+
+	id, err := eng.SubmitWithPriority(context.Background(), map[string]interface{}{"name": "rejected"}, 10)
+	if err != ErrQueueFull {
+		t.Errorf("error - got: %+v, want: %+v", err, ErrQueueFull)
+	}
+	if status, _ := eng.Status(id); status != JobDropped {
+		t.Errorf("rejected job status - got: %+v, want: %+v", status, JobDropped)
+	}
+	if metrics := eng.QueueMetrics(); !cmp.Equal(metrics, QueueMetrics{Depth: 1, Capacity: 1, Admitted: 2, Rejected: 1}) {
+		t.Errorf("metrics - got: %+v", metrics)
+	}
+}
+
+func Test_Engine_Queue_dropOldest(t *testing.T) {
+	eng, release, _ := queuedEngine(t, 2, ShedDropOldest)
+	defer close(release)
+	oldest := submitNamed(t, eng, "oldest", 0)
+	submitNamed(t, eng, "newest", 0)
+	submitNamed(t, eng, "premium", 10)
+
+	waitForJobStatus(t, eng, oldest, JobDropped)
+	result, err := eng.Result(oldest)
+	if err != nil || result.Error != ErrJobDropped {
+		t.Errorf("result error - got: %+v (%+v), want: %+v", result.Error, err, ErrJobDropped)
+	}
+
+	_, err = eng.SubmitWithPriority(context.Background(), map[string]interface{}{"name": "backfill"}, -1)
+	if err != ErrQueueFull {
+		t.Errorf("error - got: %+v, want: %+v", err, ErrQueueFull)
+	}
+	if metrics := eng.QueueMetrics(); !cmp.Equal(metrics, QueueMetrics{Depth: 2, Capacity: 2, Admitted: 4, Rejected: 1, Dropped: 1}) {
+		t.Errorf("metrics - got: %+v", metrics)
+	}
+}
+
+func Test_Engine_Queue_block(t *testing.T) {
+	eng, release, _ := queuedEngine(t, 1, ShedBlock)
+	submitNamed(t, eng, "queued", 0)
+
+	submitted := make(chan string)
+	go func() {
+		id, _ := eng.Submit(map[string]interface{}{"name": "blocked"})
+		submitted <- id
+	}()
+	select {
+	case <-submitted:
+		t.Fatalf("submission must wait for a free place in the queue")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	waitForJobStatus(t, eng, <-submitted, JobDone)
+}
+
+func Test_Engine_Queue_block_with_context(t *testing.T) {
+	eng, release, _ := queuedEngine(t, 1, ShedBlock)
+	defer close(release)
+	submitNamed(t, eng, "queued", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	id, err := eng.SubmitWithPriority(ctx, map[string]interface{}{"name": "blocked"}, 0)
+	if err != context.DeadlineExceeded {
+		t.Errorf("error - got: %+v, want: %+v", err, context.DeadlineExceeded)
+	}
+	if status, _ := eng.Status(id); status != JobDropped {
+		t.Errorf("blocked job status - got: %+v, want: %+v", status, JobDropped)
+	}
+}
+
+func Test_Engine_Queue_cancel(t *testing.T) {
+	eng, release, names := queuedEngine(t, 1, ShedBlock)
+	queued := submitNamed(t, eng, "queued", 0)
+
+	if err := eng.Cancel(queued); err != nil {
+		t.Fatalf("cancel error - got: %+v", err)
+	}
+	if status, _ := eng.Status(queued); status != JobCancelled {
+		t.Errorf("cancelled job status - got: %+v, want: %+v", status, JobCancelled)
+	}
+	if metrics := eng.QueueMetrics(); metrics.Depth != 0 {
+		t.Errorf("metrics - got: %+v", metrics)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	next, err := eng.SubmitWithPriority(ctx, map[string]interface{}{"name": "next"}, 0)
+	if err != nil {
+		t.Fatalf("submit error - got: %+v", err)
+	}
+	close(release)
+	waitForJobStatus(t, eng, next, JobDone)
+
+	expectedNames := []string{"busy", "next"}
+	if !cmp.Equal(names(), expectedNames) {
+		t.Errorf("names - got: %v, want: %v", names(), expectedNames)
+	}
+}
+
+func Test_Engine_StopQueue(t *testing.T) {
+	eng, release, _ := queuedEngine(t, 2, ShedBlock)
+	queued := submitNamed(t, eng, "queued", 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := eng.StopQueue(ctx); err != context.DeadlineExceeded {
+		t.Errorf("stop error with running job - got: %+v, want: %+v", err, context.DeadlineExceeded)
+	}
+	waitForJobStatus(t, eng, queued, JobCancelled)
+	if result, _ := eng.Result(queued); result.Error != ErrQueueStopped {
+		t.Errorf("queued job error - got: %+v, want: %+v", result.Error, ErrQueueStopped)
+	}
+	id, err := eng.Submit(map[string]interface{}{"name": "late"})
+	if err != ErrQueueStopped {
+		t.Errorf("submit error - got: %+v, want: %+v", err, ErrQueueStopped)
+	}
+	if status, _ := eng.Status(id); status != JobDropped {
+		t.Errorf("late job status - got: %+v, want: %+v", status, JobDropped)
+	}
+
+	close(release)
+	if err := eng.StopQueue(context.Background()); err != nil {
+		t.Errorf("stop error - got: %+v", err)
+	}
+
+	err = NewEngine(SequentialComputation).StopQueue(context.Background())
+	expectedError := errors.New("can't stop queue without configured queue")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -0,0 +1,124 @@
+package hoff
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitMode define how a computation behave when a node is over its rate limit.
+type RateLimitMode string
+
+const (
+	// RateLimitWait will make the computation wait until the node is under its rate limit.
+	RateLimitWait RateLimitMode = "wait"
+	// RateLimitReject will reject the node computation when the node is over its rate limit,
+	// and stop the computation.
+	RateLimitReject = "reject"
+)
+
+// RateLimit define a token bucket limiting the computations of a node,
+// the bucket hold up to Burst tokens and is refilled by Rate tokens per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+	Mode  RateLimitMode
+}
+
+// RateLimitError is the error of a node computation rejected due to its rate limit.
+type RateLimitError struct {
+	Node Node
+}
+
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("can't compute node over its rate limit: %v", e.Node.ID())
+}
+
+// ConfigureRateLimit limit the computations of a node into the system before activation.
+// The rate limit is shared by all computations of the activated node system, and of its clones, like the ones of an Engine.
+func (s *NodeSystem) ConfigureRateLimit(n Node, limit RateLimit) (bool, error) {
+	if s.activated {
+		return false, errors.New("can't configure rate limit, node system is freeze due to activation")
+	}
+	if !s.haveNode(n) {
+		return false, fmt.Errorf("can't configure rate limit of undeclared node: %+v", n)
+	}
+	if limit.Rate <= 0 {
+		return false, fmt.Errorf("can't configure rate limit without positive rate: %v", limit.Rate)
+	}
+	if limit.Burst < 1 {
+		return false, fmt.Errorf("can't configure rate limit without positive burst: %v", limit.Burst)
+	}
+	switch limit.Mode {
+	case "":
+		limit.Mode = RateLimitWait
+	case RateLimitWait, RateLimitReject:
+	default:
+		return false, fmt.Errorf("can't configure rate limit with unknown mode: %v", limit.Mode)
+	}
+	s.nodesRateLimits[n] = newRateLimiter(limit)
+	return true, nil
+}
+
+// RateLimitOfNode get the configured rate limit of a node
+func (s *NodeSystem) RateLimitOfNode(n Node) (RateLimit, bool) {
+	limiter, found := s.nodesRateLimits[n]
+	if !found {
+		return RateLimit{}, false
+	}
+	return limiter.limit, true
+}
+
+// rateLimiter is the token bucket of a rate limit.
+type rateLimiter struct {
+	mutex  sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		now:    time.Now,
+	}
+}
+
+// take a token from the bucket, and give the duration to wait before using it.
+// Without waiting, no token is taken when the bucket is empty.
+func (l *rateLimiter) take(wait bool) (time.Duration, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.refill()
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	if !wait {
+		return 0, false
+	}
+	l.tokens--
+	return time.Duration(-l.tokens / l.limit.Rate * float64(time.Second)), true
+}
+
+// giveBack put back a token who have not been used.
+func (l *rateLimiter) giveBack() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.tokens++
+	l.refill()
+}
+
+func (l *rateLimiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.limit.Rate
+	}
+	if l.tokens > float64(l.limit.Burst) {
+		l.tokens = float64(l.limit.Burst)
+	}
+	l.last = now
+}
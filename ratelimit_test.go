@@ -0,0 +1,256 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_ConfigureRateLimit(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	activatedSystem := NewNodeSystem()
+	activatedSystem.AddNode(node)
+	activatedSystem.Activate()
+
+	testCases := []struct {
+		name          string
+		givenSystem   *NodeSystem
+		givenNode     Node
+		givenLimit    RateLimit
+		expectedLimit RateLimit
+		expectedError error
+	}{
+		{
+			name:          "Can configure a rate limit",
+			givenNode:     node,
+			givenLimit:    RateLimit{Rate: 50, Burst: 5, Mode: RateLimitReject},
+			expectedLimit: RateLimit{Rate: 50, Burst: 5, Mode: RateLimitReject},
+		},
+		{
+			name:          "Can configure a rate limit waiting by default",
+			givenNode:     node,
+			givenLimit:    RateLimit{Rate: 50, Burst: 5},
+			expectedLimit: RateLimit{Rate: 50, Burst: 5, Mode: RateLimitWait},
+		},
+		{
+			name:          "Can't configure a rate limit on an activated node system",
+			givenSystem:   activatedSystem,
+			givenNode:     node,
+			givenLimit:    RateLimit{Rate: 50, Burst: 5},
+			expectedError: errors.New("can't configure rate limit, node system is freeze due to activation"),
+		},
+		{
+			name:          "Can't configure a rate limit of an undeclared node",
+			givenNode:     &SomeNode{},
+			givenLimit:    RateLimit{Rate: 50, Burst: 5},
+			expectedError: errors.New("can't configure rate limit of undeclared node: &{}"),
+		},
+		{
+			name:          "Can't configure a rate limit without rate",
+			givenNode:     node,
+			givenLimit:    RateLimit{Burst: 5},
+			expectedError: errors.New("can't configure rate limit without positive rate: 0"),
+		},
+		{
+			name:          "Can't configure a rate limit without burst",
+			givenNode:     node,
+			givenLimit:    RateLimit{Rate: 50},
+			expectedError: errors.New("can't configure rate limit without positive burst: 0"),
+		},
+		{
+			name:          "Can't configure a rate limit with an unknown mode",
+			givenNode:     node,
+			givenLimit:    RateLimit{Rate: 50, Burst: 5, Mode: "drop"},
+			expectedError: errors.New("can't configure rate limit with unknown mode: drop"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			system := testCase.givenSystem
+			if system == nil {
+				system = NewNodeSystem()
+				system.AddNode(node)
+			}
+			ok, err := system.ConfigureRateLimit(testCase.givenNode, testCase.givenLimit)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+			if ok != (testCase.expectedError == nil) {
+				t.Errorf("ok - got: %+v, want: %+v", ok, testCase.expectedError == nil)
+			}
+			limit, _ := system.RateLimitOfNode(testCase.givenNode)
+			if !cmp.Equal(limit, testCase.expectedLimit) {
+				t.Errorf("limit - got: %+v, want: %+v", limit, testCase.expectedLimit)
+			}
+		})
+	}
+}
+
+func Test_rateLimiter(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(RateLimit{Rate: 2, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	testCases := []struct {
+		name            string
+		givenDelay      time.Duration
+		givenWait       bool
+		expectedWait    time.Duration
+		expectedAllowed bool
+	}{
+		{
+			name:            "Take a token from the full bucket",
+			expectedAllowed: true,
+		},
+		{
+			name:            "Take the last token of the bucket",
+			expectedAllowed: true,
+		},
+		{
+			name: "Can't take a token from the empty bucket without waiting",
+		},
+		{
+			name:            "Take a refilled token",
+			givenDelay:      500 * time.Millisecond,
+			expectedAllowed: true,
+		},
+		{
+			name:            "Take a token to wait for from the empty bucket",
+			givenWait:       true,
+			expectedWait:    500 * time.Millisecond,
+			expectedAllowed: true,
+		},
+		{
+			name:            "Take a token to wait for after the already waited tokens",
+			givenWait:       true,
+			expectedWait:    time.Second,
+			expectedAllowed: true,
+		},
+	}
+	for _, testCase := range testCases {
+		now = now.Add(testCase.givenDelay)
+		wait, allowed := limiter.take(testCase.givenWait)
+
+		if wait != testCase.expectedWait {
+			t.Errorf("%v: wait - got: %v, want: %v", testCase.name, wait, testCase.expectedWait)
+		}
+		if allowed != testCase.expectedAllowed {
+			t.Errorf("%v: allowed - got: %v, want: %v", testCase.name, allowed, testCase.expectedAllowed)
+		}
+	}
+}
+
+func rateLimitedEngine(limit RateLimit) (*Engine, Node) {
+	callPartner, _ := NewActionNode("callPartner", func(c *Context) error {
+		c.Store("called", true)
+		return nil
+	})
+	system := NewNodeSystem()
+	system.AddNode(callPartner)
+	system.ConfigureRateLimit(callPartner, limit)
+	system.Activate()
+
+	engine := NewEngine(SequentialComputation)
+	engine.ConfigureNodeSystem(system)
+	return engine, callPartner
+}
+
+func Test_RateLimit_Reject(t *testing.T) {
+	engine, callPartner := rateLimitedEngine(RateLimit{Rate: 0.001, Burst: 1, Mode: RateLimitReject})
+
+	first := engine.Compute(map[string]interface{}{})
+	if first.Error != nil {
+		t.Fatalf("first computation error - got: %+v", first.Error)
+	}
+
+	second := engine.Compute(map[string]interface{}{})
+	expectedError := RateLimitError{Node: callPartner}
+	if !cmp.Equal(second.Error, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", second.Error, expectedError)
+	}
+	expectedReport := map[Node]ComputeState{callPartner: NewRejectComputeState(expectedError)}
+	if !cmp.Equal(second.Report, expectedReport, NodeComparator, errorComparator) {
+		t.Errorf("report - got: %+v, want: %+v", second.Report, expectedReport)
+	}
+	if _, called := second.Data["called"]; called {
+		t.Errorf("rejected node must not be computed")
+	}
+}
+
+func Test_RateLimit_Wait(t *testing.T) {
+	engine, _ := rateLimitedEngine(RateLimit{Rate: 100, Burst: 1})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		result := engine.Compute(map[string]interface{}{})
+		if result.Error != nil {
+			t.Fatalf("computation error - got: %+v", result.Error)
+		}
+		if waited := result.Trace[0].Wait > 0; waited != (i > 0) {
+			t.Errorf("computation %v must wait for the rate limit: %v - got: %+v", i, i > 0, result.Trace)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("computations must wait for the rate limit - got: %v", elapsed)
+	}
+}
+
+func Test_RateLimit_WaitCancelled(t *testing.T) {
+	engine, _ := rateLimitedEngine(RateLimit{Rate: 0.001, Burst: 1})
+	engine.Compute(map[string]interface{}{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	result := engine.ComputeContext(ctx, map[string]interface{}{})
+
+	if !errors.Is(result.Error, context.DeadlineExceeded) {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, context.DeadlineExceeded)
+	}
+}
+
+func Test_NodeSystem_Clone_RateLimit(t *testing.T) {
+	node, _ := NewActionNode("node", func(*Context) error { return nil })
+	system := NewNodeSystem()
+	system.AddNode(node)
+	system.ConfigureRateLimit(node, RateLimit{Rate: 0.001, Burst: 1, Mode: RateLimitReject})
+	system.nodesRateLimits[node].take(false)
+
+	clone := system.Clone()
+	if _, allowed := clone.nodesRateLimits[node].take(false); allowed {
+		t.Errorf("clone must share the token bucket")
+	}
+	merged := NewNodeSystem()
+	merged.Merge(system)
+	if _, allowed := merged.nodesRateLimits[node].take(false); allowed {
+		t.Errorf("merged node system must share the token bucket")
+	}
+
+	replacement, _ := NewActionNode("replacement", func(*Context) error { return nil })
+	clone.ReplaceNode(node, replacement)
+	if limit, found := clone.RateLimitOfNode(replacement); !found || limit.Rate != 0.001 {
+		t.Errorf("replacement must have the rate limit - got: %+v", limit)
+	}
+	clone.RemoveNode(replacement)
+	if _, found := clone.RateLimitOfNode(replacement); found {
+		t.Errorf("removed node must not have a rate limit")
+	}
+}
+
+func Test_RateLimit_SwapNodeSystem(t *testing.T) {
+	engine, callPartner := rateLimitedEngine(RateLimit{Rate: 0.001, Burst: 1, Mode: RateLimitReject})
+	engine.Compute(map[string]interface{}{})
+
+	clone := engine.NodeSystem().Clone()
+	clone.Activate()
+	engine.SwapNodeSystem(clone)
+
+	result := engine.Compute(map[string]interface{}{})
+	expectedError := RateLimitError{Node: callPartner}
+	if !cmp.Equal(result.Error, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", result.Error, expectedError)
+	}
+}
@@ -0,0 +1,346 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"reflect"
+	"sort"
+)
+
+// RoutingPolicy choose the variant of the engine to compute an input data on.
+// An empty, or unknown, variant name route the computation on the configured node system.
+type RoutingPolicy func(data map[string]interface{}) string
+
+// WeightedRouting route randomly the computations on variants, each variant by its weight.
+// Use an empty variant name to weight the configured node system.
+func WeightedRouting(weights map[string]int) RoutingPolicy {
+	names, total := sortedWeights(weights)
+	return func(data map[string]interface{}) string {
+		if total == 0 {
+			return ""
+		}
+		return pickWeighted(names, weights, rand.Intn(total))
+	}
+}
+
+// HashRouting route the computations on variants, each variant by its weight,
+// using a hash of the value of a key in the input data.
+// The computations with the same value are always routed on the same variant.
+func HashRouting(key string, weights map[string]int) RoutingPolicy {
+	names, total := sortedWeights(weights)
+	return func(data map[string]interface{}) string {
+		if total == 0 {
+			return ""
+		}
+		hash := fnv.New32a()
+		fmt.Fprintf(hash, "%v", data[key])
+		return pickWeighted(names, weights, int(hash.Sum32()%uint32(total)))
+	}
+}
+
+// PredicateRouting route the computations whose input data match a predicate on a variant,
+// and the others computations on another one.
+func PredicateRouting(predicate func(data map[string]interface{}) bool, matching, others string) RoutingPolicy {
+	return func(data map[string]interface{}) string {
+		if predicate(data) {
+			return matching
+		}
+		return others
+	}
+}
+
+// ShadowReport is the comparison between the result of a computation,
+// and the discarded result of the same computation on the shadow variant.
+type ShadowReport struct {
+	Variant       string
+	Result        ComputationResult
+	ShadowResult  ComputationResult
+	DifferentKeys []string
+	DifferentErr  bool
+}
+
+// HaveDifferences tell if the shadow variant have computed another output.
+func (r ShadowReport) HaveDifferences() bool {
+	return len(r.DifferentKeys) > 0 || r.DifferentErr
+}
+
+// ConfigureVariant add an activated node system to the engine under a variant name.
+func (e *Engine) ConfigureVariant(name string, system *NodeSystem) error {
+	e.systemMutex.Lock()
+	defer e.systemMutex.Unlock()
+	if name == "" {
+		return errors.New("variant need a name")
+	}
+	if _, found := e.variants[name]; found {
+		return fmt.Errorf("variant already configured: %v", name)
+	}
+	if system == nil || !system.IsActivated() {
+		return errors.New("node system need to be activated")
+	}
+	// the variants are replaced, not modified, to be used by a routed computation without lock
+	variants := make(map[string]*NodeSystem, len(e.variants)+1)
+	for variantName, variantSystem := range e.variants {
+		variants[variantName] = variantSystem
+	}
+	variants[name] = system
+	e.variants = variants
+	return nil
+}
+
+// ConfigureRouting set the policy to route the computations on the variants.
+func (e *Engine) ConfigureRouting(policy RoutingPolicy) {
+	e.systemMutex.Lock()
+	defer e.systemMutex.Unlock()
+	e.routing = policy
+}
+
+// ConfigureShadow run each computation on a shadow variant too, and give the comparison of the results to a report function.
+// The shadow computation run in background on a deep copy of the input data, and its result is discarded.
+func (e *Engine) ConfigureShadow(name string, report func(ShadowReport)) error {
+	e.systemMutex.Lock()
+	defer e.systemMutex.Unlock()
+	if _, found := e.variants[name]; !found {
+		return fmt.Errorf("can't shadow unknown variant: %v", name)
+	}
+	if report == nil {
+		return errors.New("shadow need a report function")
+	}
+	e.shadow = name
+	e.shadowReport = report
+	return nil
+}
+
+// route choose the node system of a computation, and its shadow node system if any.
+// The routing policy is called out of the engine lock, on a snapshot of the engine node systems.
+func (e *Engine) route(data map[string]interface{}) engineRoute {
+	e.systemMutex.RLock()
+	route := engineRoute{system: e.system, version: e.version, coverage: e.coverage}
+	routing, variants := e.routing, e.variants
+	shadow, shadowReport := e.shadow, e.shadowReport
+	e.systemMutex.RUnlock()
+
+	if routing != nil {
+		variant := routing(data)
+		if system, found := variants[variant]; found {
+			route = engineRoute{system: system, variant: variant, coverage: route.coverage}
+		}
+	}
+	if route.coverage != nil && route.coverage.system != route.system {
+		route.coverage = nil
+	}
+	if shadow != "" && shadow != route.variant {
+		route.shadow = variants[shadow]
+		route.shadowVariant = shadow
+		route.shadowReport = shadowReport
+	}
+	return route
+}
+
+// engineRoute is the node system chosen to run a computation, its shadow node system (if any), and the coverage recording it.
+type engineRoute struct {
+	system        *NodeSystem
+	version       int
+	variant       string
+	shadow        *NodeSystem
+	shadowVariant string
+	shadowReport  func(ShadowReport)
+	coverage      *Coverage
+}
+
+func (r engineRoute) runShadow(data map[string]interface{}, result ComputationResult) {
+	shadowResult := compute(context.Background(), r.shadow, data)
+	shadowResult.Variant = r.shadowVariant
+
+	r.shadowReport(ShadowReport{
+		Variant:       r.shadowVariant,
+		Result:        result,
+		ShadowResult:  shadowResult,
+		DifferentKeys: differentKeys(result.Data, shadowResult.Data),
+		DifferentErr:  errorText(result.Error) != errorText(shadowResult.Error),
+	})
+}
+
+func sortedWeights(weights map[string]int) ([]string, int) {
+	names := make([]string, 0, len(weights))
+	total := 0
+	for name, weight := range weights {
+		if weight > 0 {
+			names = append(names, name)
+			total += weight
+		}
+	}
+	sort.Strings(names)
+	return names, total
+}
+
+func pickWeighted(names []string, weights map[string]int, pick int) string {
+	for _, name := range names {
+		if pick < weights[name] {
+			return name
+		}
+		pick -= weights[name]
+	}
+	return ""
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		copied[key] = value
+	}
+	return copied
+}
+
+// deepCopyData copy the data with its nested maps, slices, arrays, and pointers,
+// a value seen many times (like in a cycle) being copied once.
+func deepCopyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	copier := newDeepCopier()
+	copied := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		copied[key] = copier.copyValue(value)
+	}
+	return copied
+}
+
+func deepCopyValue(value interface{}) interface{} {
+	return newDeepCopier().copyValue(value)
+}
+
+// deepCopier keep the copies of the maps, slices, and pointers already seen during a deep copy.
+type deepCopier struct {
+	copies map[deepCopyKey]reflect.Value
+}
+
+type deepCopyKey struct {
+	pointer   uintptr
+	valueType reflect.Type
+	length    int
+}
+
+func newDeepCopier() *deepCopier {
+	return &deepCopier{
+		copies: make(map[deepCopyKey]reflect.Value),
+	}
+}
+
+func (d *deepCopier) copyValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return d.copy(reflect.ValueOf(value)).Interface()
+}
+
+func (d *deepCopier) copy(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		key := deepCopyKey{pointer: value.Pointer(), valueType: value.Type()}
+		if copied, found := d.copies[key]; found {
+			return copied
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		d.copies[key] = copied
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), d.copy(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		key := deepCopyKey{pointer: value.Pointer(), valueType: value.Type(), length: value.Len()}
+		if copied, found := d.copies[key]; found {
+			return copied
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		d.copies[key] = copied
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(d.copy(value.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(d.copy(value.Index(i)))
+		}
+		return copied
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		key := deepCopyKey{pointer: value.Pointer(), valueType: value.Type()}
+		if copied, found := d.copies[key]; found {
+			return copied
+		}
+		copied := reflect.New(value.Type().Elem())
+		d.copies[key] = copied
+		copied.Elem().Set(d.copy(value.Elem()))
+		return copied
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(d.copy(value.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(d.copy(value.Field(i)))
+			}
+		}
+		return copied
+	}
+	return value
+}
+
+// deepCopyResult copy a computation result, with a deep copy of its data.
+func deepCopyResult(result ComputationResult) ComputationResult {
+	copied := result
+	copied.Data = deepCopyData(result.Data)
+	if result.Report != nil {
+		copied.Report = make(map[Node]ComputeState, len(result.Report))
+		for node, state := range result.Report {
+			copied.Report[node] = state
+		}
+	}
+	copied.Path = append([]Node(nil), result.Path...)
+	copied.Trace = append([]NodeTrace(nil), result.Trace...)
+	return copied
+}
+
+// differentKeys give the sorted keys whose values are not the same in two data.
+func differentKeys(data, other map[string]interface{}) []string {
+	keys := make([]string, 0)
+	for key, value := range data {
+		otherValue, found := other[key]
+		if !found || !reflect.DeepEqual(value, otherValue) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range other {
+		if _, found := data[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
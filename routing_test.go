@@ -0,0 +1,325 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_RoutingPolicy(t *testing.T) {
+	hashRouting := HashRouting("user", map[string]int{"": 50, "candidate": 50})
+
+	testCases := []struct {
+		name            string
+		givenPolicy     RoutingPolicy
+		givenData       map[string]interface{}
+		expectedVariant string
+	}{
+		{
+			name:            "Weighted routing on the only weighted variant",
+			givenPolicy:     WeightedRouting(map[string]int{"": 0, "candidate": 100}),
+			givenData:       map[string]interface{}{},
+			expectedVariant: "candidate",
+		},
+		{
+			name:            "Weighted routing without weight",
+			givenPolicy:     WeightedRouting(map[string]int{"candidate": 0}),
+			givenData:       map[string]interface{}{},
+			expectedVariant: "",
+		},
+		{
+			name:            "Hash routing on a key value",
+			givenPolicy:     hashRouting,
+			givenData:       map[string]interface{}{"user": "alice"},
+			expectedVariant: hashRouting(map[string]interface{}{"user": "alice", "other": true}),
+		},
+		{
+			name: "Predicate routing on matching data",
+			givenPolicy: PredicateRouting(func(data map[string]interface{}) bool {
+				return data["beta"] == true
+			}, "candidate", ""),
+			givenData:       map[string]interface{}{"beta": true},
+			expectedVariant: "candidate",
+		},
+		{
+			name: "Predicate routing on other data",
+			givenPolicy: PredicateRouting(func(data map[string]interface{}) bool {
+				return data["beta"] == true
+			}, "candidate", ""),
+			givenData:       map[string]interface{}{},
+			expectedVariant: "",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			variant := testCase.givenPolicy(testCase.givenData)
+
+			if variant != testCase.expectedVariant {
+				t.Errorf("variant - got: %+v, want: %+v", variant, testCase.expectedVariant)
+			}
+		})
+	}
+}
+
+func Test_Engine_ConfigureVariant(t *testing.T) {
+	activatedNodeSystem := NewNodeSystem()
+	activatedNodeSystem.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	if err := eng.ConfigureVariant("candidate", activatedNodeSystem); err != nil {
+		t.Errorf("error - got: %+v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		givenVariant  string
+		givenSystem   *NodeSystem
+		expectedError error
+	}{
+		{
+			name:          "Can't configure a variant without name",
+			givenVariant:  "",
+			givenSystem:   activatedNodeSystem,
+			expectedError: errors.New("variant need a name"),
+		},
+		{
+			name:          "Can't configure a variant twice",
+			givenVariant:  "candidate",
+			givenSystem:   activatedNodeSystem,
+			expectedError: errors.New("variant already configured: candidate"),
+		},
+		{
+			name:          "Can't configure a variant with unactivated node system",
+			givenVariant:  "other",
+			givenSystem:   NewNodeSystem(),
+			expectedError: errors.New("node system need to be activated"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := eng.ConfigureVariant(testCase.givenVariant, testCase.givenSystem)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+
+	err := eng.ConfigureShadow("unknown", func(ShadowReport) {})
+	expectedError := errors.New("can't shadow unknown variant: unknown")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("shadow error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Engine_Compute_with_variants(t *testing.T) {
+	storeStable, _ := NewActionNode("store", func(c *Context) error {
+		c.Store("result", "stable")
+		return nil
+	})
+	storeCandidate, _ := NewActionNode("store", func(c *Context) error {
+		c.Store("result", "candidate")
+		return nil
+	})
+	stable := NewNodeSystem()
+	stable.AddNode(storeStable)
+	stable.Activate()
+	candidate := NewNodeSystem()
+	candidate.AddNode(storeCandidate)
+	candidate.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(stable)
+	eng.ConfigureVariant("candidate", candidate)
+	eng.ConfigureRouting(PredicateRouting(func(data map[string]interface{}) bool {
+		return data["beta"] == true
+	}, "candidate", ""))
+	reports := make(chan ShadowReport, 1)
+	eng.ConfigureShadow("candidate", func(report ShadowReport) {
+		reports <- report
+	})
+
+	testCases := []struct {
+		name           string
+		givenData      map[string]interface{}
+		expectedResult ComputationResult
+		expectedShadow *ShadowReport
+	}{
+		{
+			name:      "Compute on the node system, and the shadow variant",
+			givenData: map[string]interface{}{"beta": false},
+			expectedResult: ComputationResult{
+				Data:    map[string]interface{}{"beta": false, "result": "stable"},
+				Report:  map[Node]ComputeState{storeStable: NewContinueComputeState()},
+				Path:    []Node{storeStable},
+				Version: 1,
+			},
+			expectedShadow: &ShadowReport{
+				Variant: "candidate",
+				Result: ComputationResult{
+					Data:    map[string]interface{}{"beta": false, "result": "stable"},
+					Report:  map[Node]ComputeState{storeStable: NewContinueComputeState()},
+					Path:    []Node{storeStable},
+					Version: 1,
+				},
+				ShadowResult: ComputationResult{
+					Data:    map[string]interface{}{"beta": false, "result": "candidate"},
+					Report:  map[Node]ComputeState{storeCandidate: NewContinueComputeState()},
+					Path:    []Node{storeCandidate},
+					Variant: "candidate",
+				},
+				DifferentKeys: []string{"result"},
+			},
+		},
+		{
+			name:      "Compute on the routed variant, without shadow",
+			givenData: map[string]interface{}{"beta": true},
+			expectedResult: ComputationResult{
+				Data:    map[string]interface{}{"beta": true, "result": "candidate"},
+				Report:  map[Node]ComputeState{storeCandidate: NewContinueComputeState()},
+				Path:    []Node{storeCandidate},
+				Variant: "candidate",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := eng.Compute(testCase.givenData)
+
+			if !cmp.Equal(result, testCase.expectedResult, NodeComparator, errorComparator) {
+				t.Errorf("result - got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+			if testCase.expectedShadow != nil {
+				report := <-reports
+				if !cmp.Equal(report, *testCase.expectedShadow, NodeComparator, errorComparator) {
+					t.Errorf("shadow report - got: %+v, want: %+v", report, *testCase.expectedShadow)
+				}
+				if !report.HaveDifferences() {
+					t.Errorf("shadow report must have differences")
+				}
+			}
+		})
+	}
+}
+
+func Test_Engine_Compute_with_shadow_on_nested_data(t *testing.T) {
+	appendStable, _ := NewActionNode("append", func(c *Context) error {
+		items := c.Data["order"].(map[string]interface{})
+		items["status"] = "stable"
+		return nil
+	})
+	appendCandidate, _ := NewActionNode("append", func(c *Context) error {
+		items := c.Data["order"].(map[string]interface{})
+		items["status"] = "candidate"
+		return nil
+	})
+	stable := NewNodeSystem()
+	stable.AddNode(appendStable)
+	stable.Activate()
+	candidate := NewNodeSystem()
+	candidate.AddNode(appendCandidate)
+	candidate.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(stable)
+	eng.ConfigureVariant("candidate", candidate)
+	reports := make(chan ShadowReport, 1)
+	eng.ConfigureShadow("candidate", func(report ShadowReport) {
+		reports <- report
+	})
+
+	result := eng.Compute(map[string]interface{}{"order": map[string]interface{}{"id": 1}})
+	result.Data["order"].(map[string]interface{})["status"] = "changed by the caller"
+	report := <-reports
+
+	expectedOrder := map[string]interface{}{"id": 1, "status": "stable"}
+	if !cmp.Equal(report.Result.Data["order"], expectedOrder) {
+		t.Errorf("result order - got: %+v, want: %+v", report.Result.Data["order"], expectedOrder)
+	}
+	expectedShadowOrder := map[string]interface{}{"id": 1, "status": "candidate"}
+	if !cmp.Equal(report.ShadowResult.Data["order"], expectedShadowOrder) {
+		t.Errorf("shadow order - got: %+v, want: %+v", report.ShadowResult.Data["order"], expectedShadowOrder)
+	}
+}
+
+func Test_deepCopyData(t *testing.T) {
+	type item struct {
+		Name string
+		Tags []string
+	}
+	count := 1
+	data := map[string]interface{}{
+		"map":     map[string]interface{}{"key": []interface{}{"value"}},
+		"struct":  item{Name: "item", Tags: []string{"tag"}},
+		"pointer": &count,
+		"nil":     nil,
+	}
+	copied := deepCopyData(data)
+	if !cmp.Equal(copied, data) {
+		t.Fatalf("copy - got: %+v, want: %+v", copied, data)
+	}
+
+	copied["map"].(map[string]interface{})["key"].([]interface{})[0] = "changed"
+	copied["struct"].(item).Tags[0] = "changed"
+	*copied["pointer"].(*int) = 2
+	expected := map[string]interface{}{
+		"map":     map[string]interface{}{"key": []interface{}{"value"}},
+		"struct":  item{Name: "item", Tags: []string{"tag"}},
+		"pointer": &count,
+		"nil":     nil,
+	}
+	if !cmp.Equal(data, expected) || count != 1 {
+		t.Errorf("data - got: %+v, want: %+v", data, expected)
+	}
+}
+
+func Test_Engine_Compute_with_policy_using_engine(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureRouting(func(data map[string]interface{}) string {
+		// a one-shot policy, who would deadlock if called under the engine lock
+		eng.ConfigureRouting(nil)
+		return ""
+	})
+
+	done := make(chan ComputationResult)
+	go func() {
+		done <- eng.Compute(map[string]interface{}{})
+	}()
+	select {
+	case result := <-done:
+		if result.Error != nil {
+			t.Errorf("error - got: %+v", result.Error)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("routing policy called under the engine lock")
+	}
+}
+
+func Test_deepCopyData_with_cycles(t *testing.T) {
+	type link struct {
+		Name string
+		Next *link
+	}
+	first := &link{Name: "first"}
+	first.Next = &link{Name: "second", Next: first}
+	loop := map[string]interface{}{"name": "loop"}
+	loop["self"] = loop
+
+	copied := deepCopyData(map[string]interface{}{"links": first, "loop": loop})
+
+	copiedFirst := copied["links"].(*link)
+	if copiedFirst == first || copiedFirst.Next.Next != copiedFirst || copiedFirst.Next.Name != "second" {
+		t.Errorf("copied pointer cycle - got: %+v", copiedFirst)
+	}
+	copiedLoop := copied["loop"].(map[string]interface{})
+	copiedLoop["name"] = "changed"
+	if loop["name"] != "loop" || copiedLoop["self"].(map[string]interface{})["name"] != "changed" {
+		t.Errorf("copied map cycle - got: %v", copiedLoop["name"])
+	}
+}
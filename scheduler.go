@@ -0,0 +1,403 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultHistoryLimit is the default number of runs kept in the history of each schedule of a scheduler.
+const DefaultHistoryLimit = 100
+
+// DefaultMaxCatchUp is the default number of due runs computed at once by a schedule with the CatchUpAll policy.
+const DefaultMaxCatchUp = 100
+
+// Clock give the current time, and wait for durations, to a scheduler.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// CatchUpPolicy define which runs of a schedule happen when many runs are due at once (after a downtime).
+type CatchUpPolicy string
+
+const (
+	// CatchUpSkip will run only the latest due run, the previous ones being skipped.
+	CatchUpSkip CatchUpPolicy = "skip"
+	// CatchUpAll will run all due runs in order, up to the max catch up of the schedule (the latest ones).
+	CatchUpAll = "all"
+)
+
+// RunStatus is the status of a run of a schedule.
+type RunStatus string
+
+const (
+	// RunPending tell that the run computation is not started, waiting for the previous due runs of the schedule.
+	RunPending RunStatus = "pending"
+	// RunRunning tell that the run computation is running.
+	RunRunning = "running"
+	// RunDone tell that the run computation is finished, with or without error.
+	RunDone = "done"
+	// RunSkippedOverlap tell that the run is skipped, the previous run of the schedule being still running.
+	RunSkippedOverlap = "skipped_overlap"
+	// RunSkippedMissed tell that the run is skipped, being missed (due to a downtime) with the CatchUpSkip policy,
+	// or beyond the max catch up with the CatchUpAll policy.
+	RunSkippedMissed = "skipped_missed"
+)
+
+// Schedule define when (Trigger), and with which input data (Input), an engine computation is triggered,
+// and how the runs missed during a downtime are caught up (CatchUp, CatchUpSkip by default),
+// with the CatchUpAll policy computing up to MaxCatchUp due runs at once (DefaultMaxCatchUp by default).
+// Each missed run still cost a call of the trigger, and a skipped run in the history.
+type Schedule struct {
+	Name       string
+	Trigger    Trigger
+	Input      func(scheduledAt time.Time) map[string]interface{}
+	CatchUp    CatchUpPolicy
+	MaxCatchUp int
+}
+
+// StaticInput create the input of a schedule who give a copy of the same data for each run.
+func StaticInput(data map[string]interface{}) func(time.Time) map[string]interface{} {
+	return func(time.Time) map[string]interface{} {
+		return copyData(data)
+	}
+}
+
+// RunStore keep the time of the last due run of each schedule,
+// to catch up the runs missed while the process was down when a schedule is added again.
+type RunStore interface {
+	// LastRun get the scheduled time of the last due run of a schedule, or a zero time without run
+	LastRun(schedule string) (time.Time, error)
+	// SaveRun keep the scheduled time of the last due run of a schedule
+	SaveRun(schedule string, scheduledAt time.Time) error
+}
+
+// MemoryRunStore is a RunStore who keep the last runs in memory (for a single process life).
+type MemoryRunStore struct {
+	mutex    sync.Mutex
+	lastRuns map[string]time.Time
+}
+
+// NewMemoryRunStore create an empty in-memory run store.
+func NewMemoryRunStore() *MemoryRunStore {
+	return &MemoryRunStore{
+		lastRuns: make(map[string]time.Time),
+	}
+}
+
+// LastRun get the last run of a schedule from memory.
+func (s *MemoryRunStore) LastRun(schedule string) (time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.lastRuns[schedule], nil
+}
+
+// SaveRun keep the last run of a schedule in memory.
+func (s *MemoryRunStore) SaveRun(schedule string, scheduledAt time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastRuns[schedule] = scheduledAt
+	return nil
+}
+
+// ScheduledRun hold the status, and the result (once done), of a run of a schedule,
+// and the error of the run store when saving it as the last due run of the schedule (if any).
+type ScheduledRun struct {
+	Schedule    string
+	ScheduledAt time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+	Status      RunStatus
+	Result      ComputationResult
+	SaveError   error
+}
+
+// Scheduler trigger the computations of an engine following schedules,
+// without overlapping runs of the same schedule.
+type Scheduler struct {
+	engine       *Engine
+	clock        Clock
+	runs         RunStore
+	historyLimit int
+
+	mutex     sync.Mutex
+	saving    sync.Mutex
+	schedules []*scheduleState
+	changed   chan struct{}
+	running   sync.WaitGroup
+}
+
+type scheduleState struct {
+	schedule Schedule
+	next     time.Time
+	running  bool
+	history  []*ScheduledRun
+}
+
+// NewScheduler create a scheduler without schedules of the computations of an engine.
+func NewScheduler(engine *Engine) *Scheduler {
+	return &Scheduler{
+		engine:       engine,
+		clock:        systemClock{},
+		runs:         NewMemoryRunStore(),
+		historyLimit: DefaultHistoryLimit,
+		changed:      make(chan struct{}, 1),
+	}
+}
+
+// ConfigureClock replace the system clock of the scheduler (before adding schedules), like to test the schedules.
+func (s *Scheduler) ConfigureClock(clock Clock) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.clock = clock
+}
+
+// ConfigureRunStore replace the in-memory run store of the scheduler (before adding schedules),
+// like to catch up the runs missed during a restart with a persistent store.
+func (s *Scheduler) ConfigureRunStore(store RunStore) error {
+	if store == nil {
+		return errors.New("run store need to be defined")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.runs = store
+	return nil
+}
+
+// ConfigureHistoryLimit set the number of runs kept in the history of each schedule.
+func (s *Scheduler) ConfigureHistoryLimit(limit int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.historyLimit = limit
+}
+
+// AddSchedule add a schedule whose first run is the next one of its trigger from its last run in the run store,
+// the runs missed since being caught up on the next tick, or from now without last run.
+func (s *Scheduler) AddSchedule(schedule Schedule) error {
+	if schedule.Name == "" {
+		return errors.New("can't add schedule without name")
+	}
+	if schedule.Trigger == nil {
+		return fmt.Errorf("can't add schedule without trigger: %v", schedule.Name)
+	}
+	switch schedule.CatchUp {
+	case "":
+		schedule.CatchUp = CatchUpSkip
+	case CatchUpSkip, CatchUpAll:
+	default:
+		return fmt.Errorf("can't add schedule with unknown catch up policy: %v", schedule.CatchUp)
+	}
+	if schedule.MaxCatchUp < 0 {
+		return fmt.Errorf("can't add schedule with negative max catch up: %v", schedule.MaxCatchUp)
+	}
+	if schedule.MaxCatchUp == 0 {
+		schedule.MaxCatchUp = DefaultMaxCatchUp
+	}
+	if schedule.Input == nil {
+		schedule.Input = StaticInput(map[string]interface{}{})
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, state := range s.schedules {
+		if state.schedule.Name == schedule.Name {
+			return fmt.Errorf("can't add already added schedule: %v", schedule.Name)
+		}
+	}
+	last, err := s.runs.LastRun(schedule.Name)
+	if err != nil {
+		return fmt.Errorf("can't add schedule without its last run: %v", err)
+	}
+	if last.IsZero() {
+		last = s.clock.Now()
+	}
+	next := schedule.Trigger.Next(last)
+	if next.IsZero() {
+		return fmt.Errorf("can't add schedule without next run: %v", schedule.Name)
+	}
+	s.schedules = append(s.schedules, &scheduleState{
+		schedule: schedule,
+		next:     next,
+	})
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// NextRun give the time of the next run of a schedule.
+func (s *Scheduler) NextRun(name string) (time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	state, err := s.schedule(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return state.next, nil
+}
+
+// History give the runs of a schedule in order, up to the history limit.
+func (s *Scheduler) History(name string) ([]ScheduledRun, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	state, err := s.schedule(name)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]ScheduledRun, 0, len(state.history))
+	for _, run := range state.history {
+		history = append(history, *run)
+	}
+	return history, nil
+}
+
+// Run trigger the due runs each time a run is due, until the context is done,
+// and wait for the running runs before returning the context error.
+func (s *Scheduler) Run(ctx context.Context) error {
+	select {
+	case <-s.changed:
+	default:
+	}
+	for {
+		s.mutex.Lock()
+		clock := s.clock
+		next := time.Time{}
+		for _, state := range s.schedules {
+			if !state.next.IsZero() && (next.IsZero() || state.next.Before(next)) {
+				next = state.next
+			}
+		}
+		s.mutex.Unlock()
+
+		var due <-chan time.Time
+		if !next.IsZero() {
+			due = clock.After(next.Sub(clock.Now()))
+		}
+		select {
+		case <-ctx.Done():
+			s.Wait()
+			return ctx.Err()
+		case <-s.changed:
+		case <-due:
+			s.Tick(ctx)
+		}
+	}
+}
+
+// Tick trigger the runs who are due at the current time of the clock, in background,
+// and save the last due run of each schedule in the run store, out of the scheduler lock
+// (a failed save being kept in the SaveError of the run, and only making the run due again after a restart).
+// The due runs of a schedule with a running run are skipped.
+func (s *Scheduler) Tick(ctx context.Context) {
+	s.mutex.Lock()
+	now := s.clock.Now()
+	store := s.runs
+	saves := make([]*ScheduledRun, 0)
+	for _, state := range s.schedules {
+		limit := state.schedule.MaxCatchUp
+		if state.schedule.CatchUp == CatchUpSkip {
+			limit = 1
+		}
+		due := make([]time.Time, 0, 1)
+		for !state.next.IsZero() && !state.next.After(now) {
+			if len(due) == limit {
+				s.record(state, &ScheduledRun{Schedule: state.schedule.Name, ScheduledAt: due[0], Status: RunSkippedMissed})
+				due = due[1:]
+			}
+			due = append(due, state.next)
+			state.next = state.schedule.Trigger.Next(state.next)
+		}
+		if len(due) == 0 {
+			continue
+		}
+		if state.running {
+			for _, overlapped := range due {
+				s.record(state, &ScheduledRun{Schedule: state.schedule.Name, ScheduledAt: overlapped, Status: RunSkippedOverlap})
+			}
+			saves = append(saves, state.history[len(state.history)-1])
+			continue
+		}
+
+		runs := make([]*ScheduledRun, 0, len(due))
+		for _, scheduledAt := range due {
+			run := &ScheduledRun{Schedule: state.schedule.Name, ScheduledAt: scheduledAt, Status: RunPending}
+			s.record(state, run)
+			runs = append(runs, run)
+		}
+		saves = append(saves, runs[len(runs)-1])
+		state.running = true
+		s.running.Add(1)
+		go s.execute(ctx, state, runs)
+	}
+	// the saves of a tick happen before the ones of the next tick
+	s.saving.Lock()
+	s.mutex.Unlock()
+
+	errs := make([]error, len(saves))
+	for i, run := range saves {
+		errs[i] = store.SaveRun(run.Schedule, run.ScheduledAt)
+	}
+	s.saving.Unlock()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, run := range saves {
+		run.SaveError = errs[i]
+	}
+}
+
+// Wait wait for the running runs to be done.
+func (s *Scheduler) Wait() {
+	s.running.Wait()
+}
+
+func (s *Scheduler) execute(ctx context.Context, state *scheduleState, runs []*ScheduledRun) {
+	defer s.running.Done()
+	for _, run := range runs {
+		s.mutex.Lock()
+		run.StartedAt = s.clock.Now()
+		run.Status = RunRunning
+		s.mutex.Unlock()
+
+		result := s.engine.ComputeContext(ctx, state.schedule.Input(run.ScheduledAt))
+
+		s.mutex.Lock()
+		run.Result = result
+		run.Status = RunDone
+		run.FinishedAt = s.clock.Now()
+		s.mutex.Unlock()
+	}
+	s.mutex.Lock()
+	state.running = false
+	s.mutex.Unlock()
+}
+
+func (s *Scheduler) record(state *scheduleState, run *ScheduledRun) {
+	state.history = append(state.history, run)
+	if s.historyLimit > 0 && len(state.history) > s.historyLimit {
+		state.history = state.history[len(state.history)-s.historyLimit:]
+	}
+}
+
+func (s *Scheduler) schedule(name string) (*scheduleState, error) {
+	for _, state := range s.schedules {
+		if state.schedule.Name == name {
+			return state, nil
+		}
+	}
+	return nil, fmt.Errorf("can't find schedule: %v", name)
+}
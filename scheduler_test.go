@@ -0,0 +1,366 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeClock is a clock whose time only change when advanced.
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	channel chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	channel := make(chan time.Time, 1)
+	if d <= 0 {
+		channel <- c.now
+		return channel
+	}
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), channel: channel})
+	return channel
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	timers := make([]fakeTimer, 0, len(c.timers))
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			timers = append(timers, timer)
+		} else {
+			timer.channel <- c.now
+		}
+	}
+	c.timers = timers
+}
+
+func (c *fakeClock) Timers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
+
+func schedulerEngine(release chan struct{}) *Engine {
+	reconcile, _ := NewActionNode("reconcile", func(c *Context) error {
+		if c.HaveKey("slow") {
+			<-release
+		}
+		c.Store("reconciled", true)
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(reconcile)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+	return eng
+}
+
+func runsStatus(history []ScheduledRun) []string {
+	statuses := make([]string, 0, len(history))
+	for _, run := range history {
+		statuses = append(statuses, run.ScheduledAt.Format("15:04")+" "+string(run.Status))
+	}
+	return statuses
+}
+
+func Test_Scheduler_AddSchedule(t *testing.T) {
+	scheduler := NewScheduler(schedulerEngine(nil))
+	scheduler.AddSchedule(Schedule{Name: "nightly", Trigger: Every(time.Hour)})
+
+	testCases := []struct {
+		name          string
+		givenSchedule Schedule
+		expectedError error
+	}{
+		{
+			name:          "Can add a schedule",
+			givenSchedule: Schedule{Name: "hourly", Trigger: Every(time.Hour), CatchUp: CatchUpAll},
+		},
+		{
+			name:          "Can't add a schedule without name",
+			givenSchedule: Schedule{Trigger: Every(time.Hour)},
+			expectedError: errors.New("can't add schedule without name"),
+		},
+		{
+			name:          "Can't add a schedule without trigger",
+			givenSchedule: Schedule{Name: "never"},
+			expectedError: errors.New("can't add schedule without trigger: never"),
+		},
+		{
+			name:          "Can't add a schedule with an unknown catch up policy",
+			givenSchedule: Schedule{Name: "unknown", Trigger: Every(time.Hour), CatchUp: "first"},
+			expectedError: errors.New("can't add schedule with unknown catch up policy: first"),
+		},
+		{
+			name:          "Can't add a schedule with a negative max catch up",
+			givenSchedule: Schedule{Name: "negative", Trigger: Every(time.Hour), CatchUp: CatchUpAll, MaxCatchUp: -1},
+			expectedError: errors.New("can't add schedule with negative max catch up: -1"),
+		},
+		{
+			name:          "Can't add an already added schedule",
+			givenSchedule: Schedule{Name: "nightly", Trigger: Every(time.Hour)},
+			expectedError: errors.New("can't add already added schedule: nightly"),
+		},
+		{
+			name:          "Can't add a schedule without next run",
+			givenSchedule: Schedule{Name: "stopped", Trigger: Every(0)},
+			expectedError: errors.New("can't add schedule without next run: stopped"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := scheduler.AddSchedule(testCase.givenSchedule)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+
+	_, err := scheduler.History("unknown")
+	expectedError := errors.New("can't find schedule: unknown")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Scheduler_Tick(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	release := make(chan struct{})
+	scheduler := NewScheduler(schedulerEngine(release))
+	scheduler.ConfigureClock(clock)
+	scheduler.AddSchedule(Schedule{
+		Name:    "skip",
+		Trigger: Every(time.Hour),
+		Input:   StaticInput(map[string]interface{}{"schedule": "skip"}),
+	})
+	scheduler.AddSchedule(Schedule{
+		Name:    "all",
+		Trigger: Every(time.Hour),
+		CatchUp: CatchUpAll,
+	})
+	scheduler.AddSchedule(Schedule{
+		Name:    "slow",
+		Trigger: Every(time.Hour),
+		Input:   StaticInput(map[string]interface{}{"slow": true}),
+	})
+
+	clock.Advance(30 * time.Minute)
+	scheduler.Tick(context.Background())
+	scheduler.Wait()
+	if history, _ := scheduler.History("all"); len(history) != 0 {
+		t.Errorf("history before the first run - got: %v", runsStatus(history))
+	}
+
+	clock.Advance(90 * time.Minute)
+	scheduler.Tick(context.Background())
+	waitFor(t, func() bool {
+		history, _ := scheduler.History("all")
+		return len(history) == 2 && history[1].Status == RunDone
+	})
+	clock.Advance(time.Hour)
+	scheduler.Tick(context.Background())
+	close(release)
+	scheduler.Wait()
+
+	expectedStatuses := map[string][]string{
+		"skip": {"01:00 skipped_missed", "02:00 done", "03:00 done"},
+		"all":  {"01:00 done", "02:00 done", "03:00 done"},
+		"slow": {"01:00 skipped_missed", "02:00 done", "03:00 skipped_overlap"},
+	}
+	for name, expected := range expectedStatuses {
+		history, _ := scheduler.History(name)
+		if statuses := runsStatus(history); !cmp.Equal(statuses, expected) {
+			t.Errorf("%v history - got: %v, want: %v", name, statuses, expected)
+		}
+	}
+
+	history, _ := scheduler.History("skip")
+	expectedData := map[string]interface{}{"schedule": "skip", "reconciled": true}
+	if !cmp.Equal(history[1].Result.Data, expectedData) {
+		t.Errorf("data - got: %v, want: %v", history[1].Result.Data, expectedData)
+	}
+	if next, _ := scheduler.NextRun("skip"); !next.Equal(time.Date(2019, 1, 1, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("next run - got: %v", next)
+	}
+}
+
+func Test_Scheduler_history_limit(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(schedulerEngine(nil))
+	scheduler.ConfigureClock(clock)
+	scheduler.ConfigureHistoryLimit(2)
+	scheduler.AddSchedule(Schedule{Name: "hourly", Trigger: Every(time.Hour), CatchUp: CatchUpAll})
+
+	clock.Advance(4 * time.Hour)
+	scheduler.Tick(context.Background())
+	scheduler.Wait()
+
+	history, _ := scheduler.History("hourly")
+	expectedStatuses := []string{"03:00 done", "04:00 done"}
+	if statuses := runsStatus(history); !cmp.Equal(statuses, expectedStatuses) {
+		t.Errorf("history - got: %v, want: %v", statuses, expectedStatuses)
+	}
+}
+
+func Test_Scheduler_max_catch_up(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(schedulerEngine(nil))
+	scheduler.ConfigureClock(clock)
+	scheduler.AddSchedule(Schedule{Name: "hourly", Trigger: Every(time.Hour), CatchUp: CatchUpAll, MaxCatchUp: 2})
+
+	clock.Advance(4 * time.Hour)
+	scheduler.Tick(context.Background())
+	scheduler.Wait()
+
+	history, _ := scheduler.History("hourly")
+	expectedStatuses := []string{"01:00 skipped_missed", "02:00 skipped_missed", "03:00 done", "04:00 done"}
+	if statuses := runsStatus(history); !cmp.Equal(statuses, expectedStatuses) {
+		t.Errorf("history - got: %v, want: %v", statuses, expectedStatuses)
+	}
+}
+
+// failingRunStore is a run store who fail to save, while reading the history of the scheduler.
+type failingRunStore struct {
+	*MemoryRunStore
+	scheduler *Scheduler
+}
+
+func (s *failingRunStore) SaveRun(schedule string, scheduledAt time.Time) error {
+	if _, err := s.scheduler.History(schedule); err != nil {
+		return err
+	}
+	return errors.New("store unavailable")
+}
+
+func Test_Scheduler_Tick_with_failing_run_store(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(schedulerEngine(nil))
+	scheduler.ConfigureClock(clock)
+	scheduler.ConfigureRunStore(&failingRunStore{MemoryRunStore: NewMemoryRunStore(), scheduler: scheduler})
+	scheduler.AddSchedule(Schedule{Name: "hourly", Trigger: Every(time.Hour), CatchUp: CatchUpAll})
+
+	clock.Advance(2 * time.Hour)
+	scheduler.Tick(context.Background())
+	scheduler.Wait()
+
+	history, _ := scheduler.History("hourly")
+	expectedErrors := []error{nil, errors.New("store unavailable")}
+	saveErrors := []error{history[0].SaveError, history[1].SaveError}
+	if !cmp.Equal(saveErrors, expectedErrors, errorComparator) {
+		t.Errorf("save errors - got: %v, want: %v", saveErrors, expectedErrors)
+	}
+}
+
+func Test_Scheduler_catch_up_after_downtime(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryRunStore()
+	newScheduler := func() *Scheduler {
+		scheduler := NewScheduler(schedulerEngine(nil))
+		scheduler.ConfigureClock(clock)
+		scheduler.ConfigureRunStore(store)
+		scheduler.AddSchedule(Schedule{Name: "skip", Trigger: Every(time.Hour)})
+		scheduler.AddSchedule(Schedule{Name: "all", Trigger: Every(time.Hour), CatchUp: CatchUpAll})
+		return scheduler
+	}
+
+	before := newScheduler()
+	clock.Advance(time.Hour)
+	before.Tick(context.Background())
+	before.Wait()
+
+	// the process is down from 01:30 to 04:30
+	clock.Advance(3*time.Hour + 30*time.Minute)
+	after := newScheduler()
+	if next, _ := after.NextRun("all"); !next.Equal(time.Date(2019, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("next run after downtime - got: %v", next)
+	}
+	after.Tick(context.Background())
+	after.Wait()
+
+	expectedStatuses := map[string][]string{
+		"skip": {"02:00 skipped_missed", "03:00 skipped_missed", "04:00 done"},
+		"all":  {"02:00 done", "03:00 done", "04:00 done"},
+	}
+	for name, expected := range expectedStatuses {
+		history, _ := after.History(name)
+		if statuses := runsStatus(history); !cmp.Equal(statuses, expected) {
+			t.Errorf("%v history - got: %v, want: %v", name, statuses, expected)
+		}
+	}
+	if last, _ := store.LastRun("all"); !last.Equal(time.Date(2019, 1, 1, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("last run - got: %v", last)
+	}
+
+	err := after.ConfigureRunStore(nil)
+	expectedError := errors.New("run store need to be defined")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
+
+func Test_Scheduler_Run(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(schedulerEngine(nil))
+	scheduler.ConfigureClock(clock)
+	nightly, _ := Cron("@daily")
+	scheduler.AddSchedule(Schedule{Name: "nightly", Trigger: nightly})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() {
+		stopped <- scheduler.Run(ctx)
+	}()
+
+	for day := 1; day <= 2; day++ {
+		waitFor(t, func() bool { return clock.Timers() > 0 })
+		clock.Advance(24 * time.Hour)
+		waitFor(t, func() bool {
+			history, _ := scheduler.History("nightly")
+			return len(history) == day && history[day-1].Status == RunDone
+		})
+	}
+	cancel()
+	if err := <-stopped; err != context.Canceled {
+		t.Errorf("error - got: %+v, want: %+v", err, context.Canceled)
+	}
+
+	history, _ := scheduler.History("nightly")
+	if len(history) != 2 || !history[1].ScheduledAt.Equal(time.Date(2019, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("history - got: %+v", history)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
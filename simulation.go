@@ -0,0 +1,60 @@
+package hoff
+
+import (
+	"fmt"
+)
+
+// DecisionStrategy give the branch taken by a decision node during a dry run.
+type DecisionStrategy func(node Node, c *Context) (bool, error)
+
+// ForcedDecisions is a decision strategy who take the branches of the decision nodes by their identifiers.
+// A decision node without forced branch abort the dry run.
+func ForcedDecisions(branches map[string]bool) DecisionStrategy {
+	return func(node Node, c *Context) (bool, error) {
+		branch, found := branches[node.ID()]
+		if !found {
+			return false, fmt.Errorf("can't dry run decision node without forced branch: %v", node.ID())
+		}
+		return branch, nil
+	}
+}
+
+// AlwaysDecide is a decision strategy who take the same branch for all decision nodes.
+func AlwaysDecide(branch bool) DecisionStrategy {
+	return func(node Node, c *Context) (bool, error) {
+		return branch, nil
+	}
+}
+
+// DryRun walk the node system with input data like Compute, without running the nodes,
+// the decision nodes taking the branches given by a decision strategy.
+func (e *Engine) DryRun(data map[string]interface{}, strategy DecisionStrategy) ComputationResult {
+	system, version := e.currentNodeSystem()
+	if system == nil {
+		return ComputationResult{
+			Data:  data,
+			Error: ErrNodeSystemNotConfigured,
+		}
+	}
+
+	cp, _ := NewComputation(system, NewContext(data))
+	err := cp.DryRun(strategy)
+	return ComputationResult{
+		Data:    cp.Context.Data,
+		Error:   err,
+		Report:  cp.Report,
+		Path:    cp.Path,
+		Version: version,
+	}
+}
+
+func simulateNode(node Node, c *Context, strategy DecisionStrategy) ComputeState {
+	if !node.DecideCapability() {
+		return NewContinueComputeState()
+	}
+	branch, err := strategy(node, c)
+	if err != nil {
+		return NewAbortComputeState(err)
+	}
+	return NewContinueOnBranchComputeState(branch)
+}
@@ -0,0 +1,125 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_DryRun(t *testing.T) {
+	sideEffects := 0
+	fraudCheck, _ := NewDecisionNode("fraudCheck", func(c *Context) (bool, error) {
+		sideEffects++
+		return true, nil
+	})
+	stockCheck, _ := NewDecisionNode("stockCheck", func(c *Context) (bool, error) {
+		sideEffects++
+		return false, nil
+	})
+	reject, _ := NewActionNode("reject", func(c *Context) error {
+		sideEffects++
+		return nil
+	})
+	ship, _ := NewActionNode("ship", func(c *Context) error {
+		sideEffects++
+		return nil
+	})
+	backorder, _ := NewActionNode("backorder", func(c *Context) error {
+		sideEffects++
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(fraudCheck)
+	ns.AddNode(stockCheck)
+	ns.AddNode(reject)
+	ns.AddNode(ship)
+	ns.AddNode(backorder)
+	ns.AddLinkOnBranch(fraudCheck, reject, true)
+	ns.AddLinkOnBranch(fraudCheck, stockCheck, false)
+	ns.AddLinkOnBranch(stockCheck, ship, true)
+	ns.AddLinkOnBranch(stockCheck, backorder, false)
+	ns.Activate()
+
+	eng := NewEngine(SequentialComputation)
+	eng.ConfigureNodeSystem(ns)
+
+	testCases := []struct {
+		name           string
+		givenStrategy  DecisionStrategy
+		expectedResult ComputationResult
+	}{
+		{
+			name:          "Dry run with forced decisions",
+			givenStrategy: ForcedDecisions(map[string]bool{"fraudCheck": false, "stockCheck": true}),
+			expectedResult: ComputationResult{
+				Data: map[string]interface{}{},
+				Report: map[Node]ComputeState{
+					fraudCheck: NewContinueOnBranchComputeState(false),
+					stockCheck: NewContinueOnBranchComputeState(true),
+					reject:     NewSkipComputeState(),
+					ship:       NewContinueComputeState(),
+					backorder:  NewSkipComputeState(),
+				},
+				Path:    []Node{fraudCheck, stockCheck, ship},
+				Version: 1,
+			},
+		},
+		{
+			name:          "Dry run without a forced decision",
+			givenStrategy: ForcedDecisions(map[string]bool{"fraudCheck": false}),
+			expectedResult: ComputationResult{
+				Data:  map[string]interface{}{},
+				Error: errors.New("can't dry run decision node without forced branch: stockCheck"),
+				Report: map[Node]ComputeState{
+					fraudCheck: NewContinueOnBranchComputeState(false),
+					stockCheck: NewAbortComputeState(errors.New("can't dry run decision node without forced branch: stockCheck")),
+					reject:     NewSkipComputeState(),
+				},
+				Path:    []Node{fraudCheck, stockCheck},
+				Version: 1,
+			},
+		},
+		{
+			name:          "Dry run with the same decision",
+			givenStrategy: AlwaysDecide(true),
+			expectedResult: ComputationResult{
+				Data: map[string]interface{}{},
+				Report: map[Node]ComputeState{
+					fraudCheck: NewContinueOnBranchComputeState(true),
+					stockCheck: NewSkipComputeState(),
+					reject:     NewContinueComputeState(),
+					ship:       NewSkipComputeState(),
+					backorder:  NewSkipComputeState(),
+				},
+				Path:    []Node{fraudCheck, reject},
+				Version: 1,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := eng.DryRun(map[string]interface{}{}, testCase.givenStrategy)
+
+			if !cmp.Equal(result, testCase.expectedResult, NodeComparator, errorComparator) {
+				t.Errorf("result - got: %+v, want: %+v", result, testCase.expectedResult)
+			}
+			if sideEffects != 0 {
+				t.Errorf("side effects - got: %+v, want: %+v", sideEffects, 0)
+			}
+		})
+	}
+}
+
+func Test_Computation_DryRun_without_strategy(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.Activate()
+	cp, _ := NewComputation(ns, NewContextWithoutData())
+
+	err := cp.DryRun(nil)
+	expectedError := errors.New("must have a decision strategy to dry run")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+}
@@ -12,4 +12,7 @@ const (
 	// AbortState tell the Node computation encounter an error
 	// and abort the computation
 	AbortState = "Abort"
+	// RejectState tell the Node computation is rejected due to a limit
+	// and abort the computation
+	RejectState = "Reject"
 )
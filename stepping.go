@@ -0,0 +1,138 @@
+package hoff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Step is a node reported by a stepping computation, with its compute state, and its context writes.
+type Step struct {
+	Node   Node
+	State  ComputeState
+	Writes ContextWrites
+}
+
+// Breakpoint tell why a stepping computation is paused:
+// before computing a node (Node), or after a node computation writing a context key (Key, and Step).
+type Breakpoint struct {
+	Node Node
+	Key  string
+	Step *Step
+}
+
+// AddBreakpoint pause a stepping computation before computing (or skipping) a node.
+func (cp *Computation) AddBreakpoint(n Node) {
+	if cp.breakpoints == nil {
+		cp.breakpoints = make(map[Node]bool)
+	}
+	cp.breakpoints[n] = true
+}
+
+// AddKeyBreakpoint pause a stepping computation after a node computation storing, or deleting, a context key.
+func (cp *Computation) AddKeyBreakpoint(key string) {
+	if cp.keyBreakpoints == nil {
+		cp.keyBreakpoints = make(map[string]bool)
+	}
+	cp.keyBreakpoints[key] = true
+}
+
+// ClearBreakpoints remove the breakpoints on nodes, and context keys.
+func (cp *Computation) ClearBreakpoints() {
+	cp.breakpoints = nil
+	cp.keyBreakpoints = nil
+}
+
+// ForceDecision make a decision node take a branch without running its decision function.
+func (cp *Computation) ForceDecision(n Node, branch bool) error {
+	if !n.DecideCapability() {
+		return fmt.Errorf("can't force the decision of a node without decide capability: %v", n.ID())
+	}
+	if cp.forcedDecisions == nil {
+		cp.forcedDecisions = make(map[Node]bool)
+	}
+	cp.forcedDecisions[n] = branch
+	return nil
+}
+
+// Step report the next node (computed, or skipped) of the computation, and pause.
+// The computation start on the first step, and the step have no node once the computation is finished.
+func (cp *Computation) Step() (Step, error) {
+	if !cp.started {
+		cp.start(context.Background())
+	}
+	cp.pausedAt = nil
+	if cp.finished {
+		return Step{}, nil
+	}
+	return cp.step(true)
+}
+
+// Continue run the computation until a breakpoint (returned), or until the computation is finished (without breakpoint).
+// Continuing from a breakpoint on a node compute this node.
+func (cp *Computation) Continue() (*Breakpoint, error) {
+	if !cp.started {
+		cp.start(context.Background())
+	}
+	for !cp.finished {
+		node, _, found := cp.next()
+		if found && cp.breakpoints[node] && cp.pausedAt != node {
+			cp.pausedAt = node
+			return &Breakpoint{Node: node}, nil
+		}
+		cp.pausedAt = nil
+
+		step, err := cp.step(len(cp.keyBreakpoints) > 0)
+		if err != nil {
+			return nil, err
+		}
+		if key, written := cp.writtenKeyBreakpoint(step.Writes); written {
+			return &Breakpoint{Node: step.Node, Key: key, Step: &step}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Frontier give the pending nodes of a stepping computation, in the order they will be walked.
+// Some of them can be dropped when walked, due to their ancestors reports.
+// Before the first step, the frontier is the initial nodes of the node system.
+func (cp *Computation) Frontier() []Node {
+	if !cp.started {
+		return cp.System.InitialNodes()
+	}
+	frontier := make([]Node, 0, len(cp.pending))
+	seen := make(map[Node]bool)
+	for i := len(cp.pending) - 1; i >= 0; i-- {
+		node := cp.pending[i]
+		if _, reported := cp.Report[node]; reported || seen[node] {
+			continue
+		}
+		seen[node] = true
+		frontier = append(frontier, node)
+	}
+	return frontier
+}
+
+// IsFinished tell if a stepping computation have walked all nodes, or have been aborted.
+func (cp *Computation) IsFinished() bool {
+	return cp.finished
+}
+
+func (cp *Computation) writtenKeyBreakpoint(writes ContextWrites) (string, bool) {
+	keys := make([]string, 0)
+	for key := range writes.Stored {
+		if cp.keyBreakpoints[key] {
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range writes.Deleted {
+		if cp.keyBreakpoints[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false
+	}
+	sort.Strings(keys)
+	return keys[0], true
+}
@@ -0,0 +1,178 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func steppingComputation() (*Computation, []Node) {
+	keyIsPresent, _ := NewDecisionNode("keyIsPresent", func(c *Context) (bool, error) {
+		return c.HaveKey("key"), nil
+	})
+	storeFound, _ := NewActionNode("storeFound", func(c *Context) error {
+		c.Store("found", true)
+		return nil
+	})
+	storeMissing, _ := NewActionNode("storeMissing", func(c *Context) error {
+		c.Store("found", false)
+		return nil
+	})
+	cleanKey, _ := NewActionNode("cleanKey", func(c *Context) error {
+		c.Delete("key")
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(keyIsPresent)
+	ns.AddNode(storeFound)
+	ns.AddNode(storeMissing)
+	ns.AddNode(cleanKey)
+	ns.AddLinkOnBranch(keyIsPresent, storeFound, true)
+	ns.AddLinkOnBranch(keyIsPresent, storeMissing, false)
+	ns.AddLink(storeFound, cleanKey)
+	ns.Activate()
+
+	cp, _ := NewComputation(ns, NewContext(map[string]interface{}{"key": "value"}))
+	return cp, []Node{keyIsPresent, storeFound, storeMissing, cleanKey}
+}
+
+func Test_Computation_Step(t *testing.T) {
+	cp, nodes := steppingComputation()
+	keyIsPresent, storeFound, storeMissing, cleanKey := nodes[0], nodes[1], nodes[2], nodes[3]
+
+	if frontier := cp.Frontier(); !cmp.Equal(frontier, []Node{keyIsPresent}, NodeComparator) {
+		t.Errorf("frontier before stepping - got: %+v, want: %+v", frontier, []Node{keyIsPresent})
+	}
+
+	expectedSteps := []struct {
+		step             Step
+		expectedFrontier []Node
+	}{
+		{
+			step:             Step{Node: keyIsPresent, State: NewContinueOnBranchComputeState(true), Writes: ContextWrites{Stored: map[string]interface{}{}}},
+			expectedFrontier: []Node{storeFound, storeMissing},
+		},
+		{
+			step:             Step{Node: storeFound, State: NewContinueComputeState(), Writes: ContextWrites{Stored: map[string]interface{}{"found": true}}},
+			expectedFrontier: []Node{cleanKey, storeMissing},
+		},
+		{
+			step:             Step{Node: cleanKey, State: NewContinueComputeState(), Writes: ContextWrites{Stored: map[string]interface{}{}, Deleted: []string{"key"}}},
+			expectedFrontier: []Node{storeMissing},
+		},
+		{
+			step:             Step{Node: storeMissing, State: NewSkipComputeState()},
+			expectedFrontier: []Node{},
+		},
+		{
+			step:             Step{},
+			expectedFrontier: []Node{},
+		},
+	}
+	for _, expected := range expectedSteps {
+		step, err := cp.Step()
+		if err != nil {
+			t.Fatalf("error - got: %+v", err)
+		}
+		if !cmp.Equal(step, expected.step, NodeComparator, errorComparator) {
+			t.Errorf("step - got: %+v, want: %+v", step, expected.step)
+		}
+		if frontier := cp.Frontier(); !cmp.Equal(frontier, expected.expectedFrontier, NodeComparator) {
+			t.Errorf("frontier - got: %+v, want: %+v", frontier, expected.expectedFrontier)
+		}
+	}
+	if !cp.IsFinished() || !cp.Status {
+		t.Errorf("computation must be finished")
+	}
+	if !cmp.Equal(cp.Context.Data, map[string]interface{}{"found": true}) {
+		t.Errorf("data - got: %+v, want: %+v", cp.Context.Data, map[string]interface{}{"found": true})
+	}
+}
+
+func Test_Computation_Continue(t *testing.T) {
+	cp, nodes := steppingComputation()
+	storeFound, cleanKey := nodes[1], nodes[3]
+	cp.AddBreakpoint(storeFound)
+	cp.AddKeyBreakpoint("key")
+
+	expectedBreakpoints := []*Breakpoint{
+		{Node: storeFound},
+		{Node: cleanKey, Key: "key", Step: &Step{Node: cleanKey, State: NewContinueComputeState(), Writes: ContextWrites{Stored: map[string]interface{}{}, Deleted: []string{"key"}}}},
+		nil,
+	}
+	for _, expected := range expectedBreakpoints {
+		breakpoint, err := cp.Continue()
+		if err != nil {
+			t.Fatalf("error - got: %+v", err)
+		}
+		if !cmp.Equal(breakpoint, expected, NodeComparator, errorComparator) {
+			t.Errorf("breakpoint - got: %+v, want: %+v", breakpoint, expected)
+		}
+	}
+	if !cp.IsFinished() {
+		t.Errorf("computation must be finished")
+	}
+
+	cp, _ = steppingComputation()
+	cp.AddBreakpoint(cp.System.InitialNodes()[0])
+	cp.ClearBreakpoints()
+	if breakpoint, _ := cp.Continue(); breakpoint != nil {
+		t.Errorf("breakpoint - got: %+v, want: <nil>", breakpoint)
+	}
+}
+
+func Test_Computation_ForceDecision(t *testing.T) {
+	cp, nodes := steppingComputation()
+	keyIsPresent, storeFound, storeMissing, cleanKey := nodes[0], nodes[1], nodes[2], nodes[3]
+
+	err := cp.ForceDecision(storeFound, true)
+	expectedError := errors.New("can't force the decision of a node without decide capability: storeFound")
+	if !cmp.Equal(err, expectedError, errorComparator) {
+		t.Errorf("error - got: %+v, want: %+v", err, expectedError)
+	}
+
+	cp.ForceDecision(keyIsPresent, false)
+	cp.Compute()
+	expectedReport := map[Node]ComputeState{
+		keyIsPresent: NewContinueOnBranchComputeState(false),
+		storeFound:   NewSkipComputeState(),
+		storeMissing: NewContinueComputeState(),
+		cleanKey:     NewSkipComputeState(),
+	}
+	if !cmp.Equal(cp.Report, expectedReport, NodeComparator, errorComparator) {
+		t.Errorf("report - got: %+v, want: %+v", cp.Report, expectedReport)
+	}
+}
+
+func Test_Computation_Step_with_nested_write(t *testing.T) {
+	addItem, _ := NewActionNode("addItem", func(c *Context) error {
+		order, _ := c.Read("order")
+		order.(map[string]interface{})["item"] = "book"
+		return nil
+	})
+	ns := NewNodeSystem()
+	ns.AddNode(addItem)
+	ns.Activate()
+
+	cp, _ := NewComputation(ns, NewContext(map[string]interface{}{"order": map[string]interface{}{}}))
+	step, err := cp.Step()
+	if err != nil {
+		t.Fatalf("error - got: %+v", err)
+	}
+	expectedWrites := ContextWrites{Stored: map[string]interface{}{"order": map[string]interface{}{"item": "book"}}}
+	if !cmp.Equal(step.Writes, expectedWrites) {
+		t.Errorf("writes - got: %+v, want: %+v", step.Writes, expectedWrites)
+	}
+
+	cp, _ = NewComputation(ns, NewContext(map[string]interface{}{"order": map[string]interface{}{}}))
+	cp.AddKeyBreakpoint("order")
+	breakpoint, err := cp.Continue()
+	if err != nil {
+		t.Fatalf("error - got: %+v", err)
+	}
+	if breakpoint == nil || breakpoint.Key != "order" || breakpoint.Node != addItem {
+		t.Errorf("breakpoint - got: %+v, want: a breakpoint on key order at %+v", breakpoint, addItem)
+	}
+}
@@ -0,0 +1,117 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ConfigureConcurrency set the maximum number of computations run at the same time by Run.
+// Only a parallel computation engine can have a concurrency greater than 1.
+func (e *Engine) ConfigureConcurrency(concurrency int) error {
+	if concurrency < 1 {
+		return errors.New("concurrency need to be at least 1")
+	}
+	if concurrency > 1 && e.mode != ParallelComputation {
+		return errors.New("can't have concurrency with a sequential computation mode")
+	}
+	e.concurrency = concurrency
+	return nil
+}
+
+// ConfigureOrdering tell if the results of Run must be in the same order than the inputs.
+func (e *Engine) ConfigureOrdering(ordered bool) {
+	e.ordered = ordered
+}
+
+// Run compute each input data received on a channel, and send the computation results on the returned channel.
+// At most the configured concurrency of computations are running, or waiting for their results to be received,
+// so the inputs are not consumed faster than the results.
+// The results channel is closed once the inputs channel is closed and all its computations are sent,
+// or once the context is done (cancelled, or timed out) without sending the remaining results.
+func (e *Engine) Run(ctx context.Context, in <-chan map[string]interface{}) <-chan ComputationResult {
+	out := make(chan ComputationResult)
+	go e.run(ctx, in, out)
+	return out
+}
+
+func (e *Engine) run(ctx context.Context, in <-chan map[string]interface{}, out chan<- ComputationResult) {
+	concurrency := e.runConcurrency()
+	slots := make(chan struct{}, concurrency)
+	send := func(result ComputationResult) {
+		select {
+		case out <- result:
+		case <-ctx.Done():
+		}
+		<-slots
+	}
+
+	var pending chan chan ComputationResult
+	var sent sync.WaitGroup
+	if e.ordered {
+		pending = make(chan chan ComputationResult, concurrency)
+		sent.Add(1)
+		go func() {
+			defer sent.Done()
+			for results := range pending {
+				send(<-results)
+			}
+		}()
+	}
+
+	for {
+		data, ok := e.receive(ctx, in, slots)
+		if !ok {
+			break
+		}
+		if e.ordered {
+			results := make(chan ComputationResult, 1)
+			pending <- results
+			go func() {
+				results <- e.ComputeContext(ctx, data)
+			}()
+		} else {
+			sent.Add(1)
+			go func() {
+				defer sent.Done()
+				send(e.ComputeContext(ctx, data))
+			}()
+		}
+	}
+
+	if pending != nil {
+		close(pending)
+	}
+	sent.Wait()
+	close(out)
+}
+
+// receive wait for a free slot, and for an input data to compute.
+func (e *Engine) receive(ctx context.Context, in <-chan map[string]interface{}, slots chan struct{}) (map[string]interface{}, bool) {
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false
+	}
+	select {
+	case data, ok := <-in:
+		if !ok {
+			<-slots
+		}
+		return data, ok
+	case <-ctx.Done():
+		<-slots
+		return nil, false
+	}
+}
+
+func (e *Engine) runConcurrency() int {
+	if e.mode != ParallelComputation {
+		return 1
+	}
+	if e.concurrency > 0 {
+		return e.concurrency
+	}
+	return runtime.NumCPU()
+}
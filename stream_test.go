@@ -0,0 +1,136 @@
+package hoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Engine_ConfigureConcurrency(t *testing.T) {
+	testCases := []struct {
+		name             string
+		givenMode        ComputationMode
+		givenConcurrency int
+		expectedError    error
+	}{
+		{
+			name:             "Can configure concurrency on parallel computation mode",
+			givenMode:        ParallelComputation,
+			givenConcurrency: 4,
+		},
+		{
+			name:             "Can configure one computation on sequential computation mode",
+			givenMode:        SequentialComputation,
+			givenConcurrency: 1,
+		},
+		{
+			name:             "Can't configure concurrency on sequential computation mode",
+			givenMode:        SequentialComputation,
+			givenConcurrency: 4,
+			expectedError:    errors.New("can't have concurrency with a sequential computation mode"),
+		},
+		{
+			name:             "Can't configure concurrency without computation",
+			givenMode:        ParallelComputation,
+			givenConcurrency: 0,
+			expectedError:    errors.New("concurrency need to be at least 1"),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewEngine(testCase.givenMode).ConfigureConcurrency(testCase.givenConcurrency)
+
+			if !cmp.Equal(err, testCase.expectedError, errorComparator) {
+				t.Errorf("error - got: %+v, want: %+v", err, testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Engine_Run(t *testing.T) {
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	sleepAction, _ := NewActionNode("sleepAction", func(c *Context) error {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		delay, _ := c.Read("delay")
+		time.Sleep(delay.(time.Duration))
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return nil
+	})
+
+	ns := NewNodeSystem()
+	ns.AddNode(sleepAction)
+	ns.Activate()
+
+	eng := NewEngine(ParallelComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureConcurrency(3)
+	eng.ConfigureOrdering(true)
+
+	in := make(chan map[string]interface{})
+	go func() {
+		for i := 0; i < 9; i++ {
+			in <- map[string]interface{}{
+				"index": i,
+				"delay": time.Duration(9-i) * time.Millisecond,
+			}
+		}
+		close(in)
+	}()
+
+	indexes := make([]int, 0)
+	for result := range eng.Run(context.Background(), in) {
+		if result.Error != nil {
+			t.Errorf("result error - got: %+v", result.Error)
+		}
+		indexes = append(indexes, result.Data["index"].(int))
+	}
+
+	expectedIndexes := []int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	if !cmp.Equal(indexes, expectedIndexes) {
+		t.Errorf("ordered results - got: %+v, want: %+v", indexes, expectedIndexes)
+	}
+	if maxRunning > 3 {
+		t.Errorf("concurrency - got: %+v, want at most: %+v", maxRunning, 3)
+	}
+}
+
+func Test_Engine_Run_cancelled(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.Activate()
+
+	eng := NewEngine(ParallelComputation)
+	eng.ConfigureNodeSystem(ns)
+	eng.ConfigureConcurrency(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan map[string]interface{})
+	out := eng.Run(ctx, in)
+
+	in <- map[string]interface{}{}
+	<-out
+	cancel()
+
+	select {
+	case _, open := <-out:
+		if open {
+			t.Errorf("results channel must be closed once the context is cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("results channel must be closed once the context is cancelled")
+	}
+}
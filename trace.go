@@ -0,0 +1,56 @@
+package hoff
+
+import (
+	"time"
+)
+
+// NodeTrace hold the duration waited by a node computation for the limits of the node (rate limit, and bulkhead).
+type NodeTrace struct {
+	Node Node
+	Wait time.Duration
+}
+
+// limitedCompute compute a node under its limits, and trace the duration waited for them.
+// The rate limit is waited for, or reject the node computation,
+// and the bulkhead is waited for until a place is free.
+func (cp *Computation) limitedCompute(node Node) ComputeState {
+	limiter, limited := cp.System.nodesRateLimits[node]
+	bulkhead, isolated := cp.System.nodesBulkheads[node]
+	if !limited && !isolated {
+		return node.Compute(cp.Context)
+	}
+
+	trace := NodeTrace{Node: node}
+	if limited {
+		delay, allowed := limiter.take(limiter.limit.Mode != RateLimitReject)
+		if !allowed {
+			cp.Trace = append(cp.Trace, trace)
+			return NewRejectComputeState(RateLimitError{Node: node})
+		}
+		if delay > 0 {
+			start := time.Now()
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-cp.ctx.Done():
+				limiter.giveBack()
+				trace.Wait = time.Since(start)
+				cp.Trace = append(cp.Trace, trace)
+				return NewAbortComputeState(cp.ctx.Err())
+			case <-timer.C:
+				trace.Wait = delay
+			}
+		}
+	}
+	if isolated {
+		wait, err := bulkhead.enter(cp.ctx)
+		trace.Wait += wait
+		if err != nil {
+			cp.Trace = append(cp.Trace, trace)
+			return NewAbortComputeState(err)
+		}
+		defer bulkhead.leave()
+	}
+	cp.Trace = append(cp.Trace, trace)
+	return node.Compute(cp.Context)
+}
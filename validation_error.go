@@ -0,0 +1,69 @@
+package hoff
+
+import (
+	"fmt"
+)
+
+// LinkSide tell on which side of a NodeLink a node is used.
+type LinkSide string
+
+const (
+	// FromSide is the side of the node where a NodeLink start.
+	FromSide LinkSide = "from"
+	// ToSide is the side of the node where a NodeLink end.
+	ToSide = "to"
+)
+
+// DecisionNodeWithoutLinkError is a validation error of a NodeSystem
+// raised when a decision node have no link starting from it.
+type DecisionNodeWithoutLinkError struct {
+	Node Node
+}
+
+func (e DecisionNodeWithoutLinkError) Error() string {
+	return fmt.Sprintf("can't have decision node without link from it: %+v", e.Node)
+}
+
+// CycleError is a validation error of a NodeSystem
+// raised when some links form a cycle between nodes.
+type CycleError struct {
+	Links []NodeLink
+}
+
+func (e CycleError) Error() string {
+	return fmt.Sprintf("Can't have cycle in links between nodes: %+v", e.Links)
+}
+
+// UndeclaredNodeError is a validation error of a NodeSystem
+// raised when a link use a node who is not declared in the system.
+type UndeclaredNodeError struct {
+	Node Node
+	Link NodeLink
+	Side LinkSide
+}
+
+func (e UndeclaredNodeError) Error() string {
+	return fmt.Sprintf("can't have undeclared node '%+v' as '%v' in branch link %+v", e.Node, e.Side, e.Link)
+}
+
+// MultipleInstancesError is a validation error of a NodeSystem
+// raised when the same node is declared more than once.
+type MultipleInstancesError struct {
+	Node  Node
+	Count int
+}
+
+func (e MultipleInstancesError) Error() string {
+	return fmt.Sprintf("can't have multiple instances (%v) of the same node: %+v", e.Count, e.Node)
+}
+
+// MissingJoinModeError is a validation error of a NodeSystem
+// raised when multiple links go to the same node without a join mode on it.
+type MissingJoinModeError struct {
+	Node  Node
+	Count int
+}
+
+func (e MissingJoinModeError) Error() string {
+	return fmt.Sprintf("can't have multiple links (%v) to the same node: %+v without join mode", e.Count, e.Node)
+}
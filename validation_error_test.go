@@ -0,0 +1,109 @@
+package hoff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_NodeSystem_IsValid_typed_errors(t *testing.T) {
+	undeclaredActionNode, _ := NewActionNode("undeclaredActionNode", func(*Context) error { return nil })
+
+	testCases := []struct {
+		name          string
+		givenSystem   func() *NodeSystem
+		expectedError error
+	}{
+		{
+			name: "Can find a decision node without link",
+			givenSystem: func() *NodeSystem {
+				ns := NewNodeSystem()
+				ns.AddNode(alwaysTrueDecisionNode)
+				return ns
+			},
+			expectedError: DecisionNodeWithoutLinkError{Node: alwaysTrueDecisionNode},
+		},
+		{
+			name: "Can find a cycle",
+			givenSystem: func() *NodeSystem {
+				ns := NewNodeSystem()
+				ns.AddNode(someActionNode)
+				ns.AddNode(anotherActionNode)
+				ns.AddLink(someActionNode, anotherActionNode)
+				ns.AddLink(anotherActionNode, someActionNode)
+				return ns
+			},
+			expectedError: CycleError{Links: []NodeLink{
+				newNodeLink(someActionNode, anotherActionNode),
+				newNodeLink(anotherActionNode, someActionNode),
+			}},
+		},
+		{
+			name: "Can find an undeclared node",
+			givenSystem: func() *NodeSystem {
+				ns := NewNodeSystem()
+				ns.AddNode(someActionNode)
+				ns.AddLink(someActionNode, undeclaredActionNode)
+				return ns
+			},
+			expectedError: UndeclaredNodeError{
+				Node: undeclaredActionNode,
+				Link: newNodeLink(someActionNode, undeclaredActionNode),
+				Side: ToSide,
+			},
+		},
+		{
+			name: "Can find multiple instances of a node",
+			givenSystem: func() *NodeSystem {
+				ns := NewNodeSystem()
+				ns.AddNode(someActionNode)
+				ns.AddNode(someActionNode)
+				return ns
+			},
+			expectedError: MultipleInstancesError{Node: someActionNode, Count: 2},
+		},
+		{
+			name: "Can find a missing join mode",
+			givenSystem: func() *NodeSystem {
+				ns := NewNodeSystem()
+				ns.AddNode(alwaysTrueDecisionNode)
+				ns.AddNode(someActionNode)
+				ns.AddNode(anotherActionNode)
+				ns.AddLinkOnBranch(alwaysTrueDecisionNode, anotherActionNode, true)
+				ns.AddLink(someActionNode, anotherActionNode)
+				return ns
+			},
+			expectedError: MissingJoinModeError{Node: anotherActionNode, Count: 2},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, errs := testCase.givenSystem().IsValid()
+
+			if len(errs) != 1 {
+				t.Fatalf("errors - got: %+v, want only: %+v", errs, testCase.expectedError)
+			}
+			if !cmp.Equal(errs[0], testCase.expectedError, NodeComparator) {
+				t.Errorf("error - got: %#v, want: %#v", errs[0], testCase.expectedError)
+			}
+		})
+	}
+}
+
+func Test_CycleError_As(t *testing.T) {
+	ns := NewNodeSystem()
+	ns.AddNode(someActionNode)
+	ns.AddNode(anotherActionNode)
+	ns.AddLink(someActionNode, anotherActionNode)
+	ns.AddLink(anotherActionNode, someActionNode)
+	_, errs := ns.IsValid()
+
+	var cycleErr CycleError
+	if !errors.As(errs[0], &cycleErr) {
+		t.Fatalf("error %+v must be a CycleError", errs[0])
+	}
+	if cycleErr.Links[0].From != someActionNode || cycleErr.Links[0].To != anotherActionNode {
+		t.Errorf("cycle first link - got: %+v", cycleErr.Links[0])
+	}
+}